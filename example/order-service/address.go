@@ -0,0 +1,223 @@
+package order
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrAddressNotParsed represents an error returned by an address value type (aka value objects)
+	ErrAddressNotParsed = errors.New("could not parse address")
+)
+
+const (
+	maxAddressLines     = 3
+	maxAddressFieldSize = 100
+)
+
+// postalCodes maps the supported ISO 3166-1 alpha-2 countries to the format of their postal codes
+// A nil format means the country does not use postal codes
+var postalCodes = map[string]*regexp.Regexp{
+	"AE": nil,
+	"AT": regexp.MustCompile(`^[0-9]{4}$`),
+	"AU": regexp.MustCompile(`^[0-9]{4}$`),
+	"BE": regexp.MustCompile(`^[0-9]{4}$`),
+	"CA": regexp.MustCompile(`^[A-Z][0-9][A-Z] ?[0-9][A-Z][0-9]$`),
+	"CH": regexp.MustCompile(`^[0-9]{4}$`),
+	"DE": regexp.MustCompile(`^[0-9]{5}$`),
+	"DK": regexp.MustCompile(`^[0-9]{4}$`),
+	"ES": regexp.MustCompile(`^[0-9]{5}$`),
+	"FR": regexp.MustCompile(`^[0-9]{5}$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}[0-9][A-Z0-9]? ?[0-9][A-Z]{2}$`),
+	"HK": nil,
+	"IE": regexp.MustCompile(`^[A-Z][0-9][0-9W] ?[A-Z0-9]{4}$`),
+	"IT": regexp.MustCompile(`^[0-9]{5}$`),
+	"JP": regexp.MustCompile(`^[0-9]{3}-?[0-9]{4}$`),
+	"NL": regexp.MustCompile(`^[0-9]{4} ?[A-Z]{2}$`),
+	"NO": regexp.MustCompile(`^[0-9]{4}$`),
+	"PL": regexp.MustCompile(`^[0-9]{2}-[0-9]{3}$`),
+	"PT": regexp.MustCompile(`^[0-9]{4}-[0-9]{3}$`),
+	"SE": regexp.MustCompile(`^[0-9]{3} ?[0-9]{2}$`),
+	"US": regexp.MustCompile(`^[0-9]{5}(-[0-9]{4})?$`),
+}
+
+// Address represents the address an order ships to
+// It can only be built through ParseAddress, so that every non-zero Address is valid
+type Address struct {
+	recipient  string
+	lines      []string
+	city       string
+	postalCode string
+	country    string
+}
+
+// ParseAddress returns an Address or an error if the given values do not form a valid address
+// The country is an ISO 3166-1 alpha-2 code and the postal code must match the format used by the country
+func ParseAddress(recipient string, lines []string, city, postalCode, country string) (Address, error) {
+	a := Address{
+		recipient:  strings.TrimSpace(recipient),
+		city:       strings.TrimSpace(city),
+		postalCode: strings.ToUpper(strings.TrimSpace(postalCode)),
+		country:    strings.ToUpper(strings.TrimSpace(country)),
+	}
+	for _, l := range lines {
+		if l = strings.TrimSpace(l); l != "" {
+			a.lines = append(a.lines, l)
+		}
+	}
+
+	if err := a.validate(); err != nil {
+		return Address{}, fmt.Errorf("%w: %w", ErrAddressNotParsed, err)
+	}
+
+	return a, nil
+}
+
+func (a Address) validate() error {
+	if err := validateAddressField("recipient", a.recipient); err != nil {
+		return err
+	}
+
+	if len(a.lines) == 0 || len(a.lines) > maxAddressLines {
+		return fmt.Errorf("must have between 1 and %d lines", maxAddressLines)
+	}
+	for _, l := range a.lines {
+		if err := validateAddressField("line", l); err != nil {
+			return err
+		}
+	}
+
+	if err := validateAddressField("city", a.city); err != nil {
+		return err
+	}
+
+	format, ok := postalCodes[a.country]
+	if !ok {
+		return fmt.Errorf("country %q not supported", a.country)
+	}
+
+	switch {
+	case format == nil && a.postalCode != "":
+		return fmt.Errorf("postal code not used in %s", a.country)
+	case format != nil && !format.MatchString(a.postalCode):
+		return fmt.Errorf("postal code %q not valid in %s", a.postalCode, a.country)
+	}
+
+	return nil
+}
+
+func validateAddressField(name, value string) error {
+	switch {
+	case value == "":
+		return fmt.Errorf("missing %s", name)
+	case utf8.RuneCountInString(value) > maxAddressFieldSize:
+		return fmt.Errorf("%s longer than %d characters", name, maxAddressFieldSize)
+	case strings.ContainsAny(value, "\r\n"):
+		return fmt.Errorf("%s spans multiple lines", name)
+	default:
+		return nil
+	}
+}
+
+// Recipient returns the name of who receives the order
+func (a Address) Recipient() string {
+	return a.recipient
+}
+
+// Lines returns the street lines of the address
+func (a Address) Lines() []string {
+	return append([]string(nil), a.lines...)
+}
+
+// City returns the city of the address
+func (a Address) City() string {
+	return a.city
+}
+
+// PostalCode returns the postal code of the address, empty for countries not using them
+func (a Address) PostalCode() string {
+	return a.postalCode
+}
+
+// Country returns the ISO 3166-1 alpha-2 code of the country of the address
+func (a Address) Country() string {
+	return a.country
+}
+
+// IsZero reports whether a represents the zero Address
+func (a Address) IsZero() bool {
+	return a.country == ""
+}
+
+// Equal reports whether a and b represent the same address
+func (a Address) Equal(b Address) bool {
+	if len(a.lines) != len(b.lines) {
+		return false
+	}
+	for i := range a.lines {
+		if a.lines[i] != b.lines[i] {
+			return false
+		}
+	}
+
+	return a.recipient == b.recipient && a.city == b.city && a.postalCode == b.postalCode && a.country == b.country
+}
+
+// String returns the Address as a single line string
+func (a Address) String() string {
+	if a.IsZero() {
+		return ""
+	}
+
+	parts := append([]string{a.recipient}, a.lines...)
+	parts = append(parts, strings.TrimSpace(a.postalCode+" "+a.city), a.country)
+	return strings.Join(parts, ", ")
+}
+
+type addressJSON struct {
+	Recipient  string   `json:"recipient"`
+	Lines      []string `json:"lines"`
+	City       string   `json:"city"`
+	PostalCode string   `json:"postal_code,omitempty"`
+	Country    string   `json:"country"`
+}
+
+// MarshalJSON encodes the Address as JSON
+func (a Address) MarshalJSON() ([]byte, error) {
+	if a.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(addressJSON{
+		Recipient:  a.recipient,
+		Lines:      a.lines,
+		City:       a.city,
+		PostalCode: a.postalCode,
+		Country:    a.country,
+	})
+}
+
+// UnmarshalJSON decodes the Address from JSON, validating it as ParseAddress does
+func (a *Address) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*a = Address{}
+		return nil
+	}
+
+	var raw addressJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return fmt.Errorf("%w: %w", ErrAddressNotParsed, err)
+	}
+
+	parsed, err := ParseAddress(raw.Recipient, raw.Lines, raw.City, raw.PostalCode, raw.Country)
+	if err != nil {
+		return err
+	}
+
+	*a = parsed
+	return nil
+}
@@ -0,0 +1,121 @@
+package order_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/organization/order-service"
+)
+
+func TestParseAddress(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		tests := map[string]struct {
+			postalCode string
+			country    string
+		}{
+			"germany":        {postalCode: "10115", country: "DE"},
+			"united kingdom": {postalCode: "sw1a 1aa", country: "GB"},
+			"united states":  {postalCode: "94105-1804", country: "us"},
+			"netherlands":    {postalCode: "1012 AB", country: "NL"},
+			"hong kong":      {postalCode: "", country: "HK"},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				a, err := ParseAddress(" Jane Doe ", []string{"Main Street 1", "", "Floor 2"}, "Springfield", tt.postalCode, tt.country)
+				if err != nil {
+					t.Fatalf("could not parse address: %s", err)
+				}
+
+				if a.Recipient() != "Jane Doe" || a.City() != "Springfield" {
+					t.Errorf("could not match address: %s", a)
+				}
+
+				if lines := a.Lines(); len(lines) != 2 || lines[0] != "Main Street 1" || lines[1] != "Floor 2" {
+					t.Errorf("could not match address lines: %v", lines)
+				}
+
+				if a.IsZero() {
+					t.Errorf("could not match a non zero address: %s", a)
+				}
+			})
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := map[string]struct {
+			recipient  string
+			lines      []string
+			city       string
+			postalCode string
+			country    string
+		}{
+			"missing recipient":      {lines: []string{"Main Street 1"}, city: "Berlin", postalCode: "10115", country: "DE"},
+			"missing lines":          {recipient: "Jane Doe", city: "Berlin", postalCode: "10115", country: "DE"},
+			"too many lines":         {recipient: "Jane Doe", lines: []string{"a", "b", "c", "d"}, city: "Berlin", postalCode: "10115", country: "DE"},
+			"multiline line":         {recipient: "Jane Doe", lines: []string{"Main\nStreet 1"}, city: "Berlin", postalCode: "10115", country: "DE"},
+			"missing city":           {recipient: "Jane Doe", lines: []string{"Main Street 1"}, postalCode: "10115", country: "DE"},
+			"unknown country":        {recipient: "Jane Doe", lines: []string{"Main Street 1"}, city: "Berlin", postalCode: "10115", country: "XX"},
+			"invalid postal code":    {recipient: "Jane Doe", lines: []string{"Main Street 1"}, city: "Berlin", postalCode: "1011", country: "DE"},
+			"foreign postal code":    {recipient: "Jane Doe", lines: []string{"Main Street 1"}, city: "London", postalCode: "10115", country: "GB"},
+			"unexpected postal code": {recipient: "Jane Doe", lines: []string{"Main Street 1"}, city: "Hong Kong", postalCode: "999077", country: "HK"},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				a, err := ParseAddress(tt.recipient, tt.lines, tt.city, tt.postalCode, tt.country)
+				if !errors.Is(err, ErrAddressNotParsed) {
+					t.Fatalf("could not match error: %s", err)
+				}
+
+				if !a.IsZero() {
+					t.Fatalf("could not match a zero address: %s", a)
+				}
+			})
+		}
+	})
+}
+
+func TestAddress_Lines(t *testing.T) {
+	a := addressHelper(t)
+	a.Lines()[0] = "Changed"
+
+	if a.Lines()[0] == "Changed" {
+		t.Error("could change the address through its lines")
+	}
+}
+
+func TestAddress_MarshalJSON(t *testing.T) {
+	a := addressHelper(t)
+	b, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("could not marshal address: %s", err)
+	}
+
+	var got Address
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("could not unmarshal address: %s", err)
+	}
+
+	if !got.Equal(a) {
+		t.Error("could not match address once unmarshalled")
+		t.Errorf("got: %s", got)
+		t.Errorf("want: %s", a)
+	}
+
+	if err := json.Unmarshal([]byte(`{"recipient":"Jane Doe","lines":["Main Street 1"],"city":"Berlin","postal_code":"1","country":"DE"}`), &got); !errors.Is(err, ErrAddressNotParsed) {
+		t.Errorf("could not match error: %s", err)
+	}
+}
+
+func addressHelper(t *testing.T) Address {
+	t.Helper()
+
+	a, err := ParseAddress("Jane Doe", []string{"Invalidenstraße 116"}, "Berlin", "10115", "DE")
+	if err != nil {
+		t.Fatalf("could not parse address: %s", err)
+	}
+
+	return a
+}
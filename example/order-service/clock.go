@@ -0,0 +1,60 @@
+package order
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	_ Clock = SystemClock{}
+	_ Clock = &FakeClock{}
+)
+
+// Clock represents the source of time used by the order aggregate to timestamp its commands
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock represents a Clock reading the time of the system
+type SystemClock struct{}
+
+// Now returns the current time of the system
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock represents a Clock that only moves when told to
+// It is meant to be used in tests and to replay commands at a given time
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock set to the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the time the clock is set to
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Set sets the clock to the given time
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+// Advance moves the clock forward by the given duration
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
@@ -0,0 +1,33 @@
+package order_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/organization/order-service"
+)
+
+func TestFakeClock(t *testing.T) {
+	now := time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC)
+	clock := NewFakeClock(now)
+
+	if got := clock.Now(); !got.Equal(now) {
+		t.Error("could not match time")
+		t.Errorf("got: %s", got)
+		t.Errorf("want: %s", now)
+	}
+
+	clock.Advance(90 * time.Minute)
+	if got, want := clock.Now(), now.Add(90*time.Minute); !got.Equal(want) {
+		t.Error("could not match advanced time")
+		t.Errorf("got: %s", got)
+		t.Errorf("want: %s", want)
+	}
+
+	clock.Set(now)
+	if got := clock.Now(); !got.Equal(now) {
+		t.Error("could not match set time")
+		t.Errorf("got: %s", got)
+		t.Errorf("want: %s", now)
+	}
+}
@@ -3,18 +3,24 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"github.com/damianopetrungaro/golog"
 	"github.com/damianopetrungaro/golog/opentelemetry"
 	_ "github.com/lib/pq"
 	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/publisher"
 	"github.com/organization/order-service/cmd/internal/repo/cache"
 	"github.com/organization/order-service/cmd/internal/repo/instrument"
+	"github.com/organization/order-service/cmd/internal/repo/memory"
 	"github.com/organization/order-service/cmd/internal/repo/postgres"
+	"github.com/organization/order-service/cmd/internal/repo/sqlite"
 	"github.com/organization/order-service/internal"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -22,11 +28,35 @@ import (
 // Feel free to open PR to make this more structured for a better real-world CLI example :)
 func main() {
 
-	var action, id, number, userID string
-	flag.StringVar(&action, "action", "", "place, order, deliver")
-	flag.StringVar(&number, "number", "", "order number to use when placing an order")
+	var action, id, tenant, userID, items, currency, reason string
+	var recipient, lines, city, postalCode, country string
+	var at string
+	var placedBy, statuses, cursor string
+	var placedAt, shippedAt, deliveredAt timeRange
+	var limit int
+	flag.StringVar(&action, "action", "", "place, order, deliver, cancel, change_address, list, evict, purge")
+	flag.StringVar(&tenant, "tenant", "default", "tenant (sales channel) to use when placing an order")
 	flag.StringVar(&userID, "user_id", "", "user id to use when placing an order")
-	flag.StringVar(&id, "id", "", "order id to use when delivering/shipping an order")
+	flag.StringVar(&items, "items", "", "items to use when placing an order, as comma separated sku:quantity:unit_price")
+	flag.StringVar(&currency, "currency", "EUR", "currency of the unit prices to use when placing an order")
+	flag.StringVar(&id, "id", "", "order id to use when delivering/shipping/cancelling an order or evicting it from the cache")
+	flag.StringVar(&reason, "reason", "", "reason code to use when cancelling an order")
+	flag.StringVar(&recipient, "recipient", "", "recipient to use when changing the shipping address of an order")
+	flag.StringVar(&lines, "lines", "", "address lines to use when changing the shipping address of an order, as pipe separated values")
+	flag.StringVar(&city, "city", "", "city to use when changing the shipping address of an order")
+	flag.StringVar(&postalCode, "postal_code", "", "postal code to use when changing the shipping address of an order")
+	flag.StringVar(&country, "country", "", "ISO 3166-1 alpha-2 country code to use when changing the shipping address of an order")
+	flag.StringVar(&placedBy, "placed_by", "", "user id to use when listing orders")
+	flag.StringVar(&statuses, "status", "", "statuses to use when listing orders, as comma separated values")
+	flag.StringVar(&placedAt.from, "placed_from", "", "RFC 3339 time orders were placed from to use when listing orders")
+	flag.StringVar(&placedAt.to, "placed_to", "", "RFC 3339 time orders were placed before to use when listing orders")
+	flag.StringVar(&shippedAt.from, "shipped_from", "", "RFC 3339 time orders were shipped from to use when listing orders")
+	flag.StringVar(&shippedAt.to, "shipped_to", "", "RFC 3339 time orders were shipped before to use when listing orders")
+	flag.StringVar(&deliveredAt.from, "delivered_from", "", "RFC 3339 time orders were delivered from to use when listing orders")
+	flag.StringVar(&deliveredAt.to, "delivered_to", "", "RFC 3339 time orders were delivered before to use when listing orders")
+	flag.IntVar(&limit, "limit", 0, "maximum number of orders per page to use when listing orders")
+	flag.StringVar(&cursor, "cursor", "", "token of the page to use when listing orders, as returned by a previous listing")
+	flag.StringVar(&at, "at", "", "RFC 3339 time to run the action at, used to replay historical commands")
 	flag.Parse()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -37,10 +67,126 @@ func main() {
 		flusher.Flush()
 	}()
 
-	db := newDB(ctx, logger)
-	repo := newRepo(db, logger)
+	clock, err := newClock(at)
+	if err != nil {
+		logger.With(golog.Err(err)).Error(ctx, "time was not valid")
+		flusher.Flush()
+		os.Exit(1)
+	}
+
+	formats, err := newNumberFormats(os.Getenv("ORDER_NUMBER_PREFIXES"))
+	if err != nil {
+		logger.With(golog.Err(err)).Error(ctx, "order number prefixes were not valid")
+		flusher.Flush()
+		os.Exit(1)
+	}
+
+	lockMode, err := newLockMode(os.Getenv("ORDER_LOCK_MODE"))
+	if err != nil {
+		logger.With(golog.Err(err)).Error(ctx, "order lock mode was not valid")
+		flusher.Flush()
+		os.Exit(1)
+	}
+
+	codec, err := newCursorCodec(os.Getenv("ORDER_CURSOR_KEY"))
+	if err != nil {
+		logger.With(golog.Err(err)).Error(ctx, "cursor key was not valid")
+		flusher.Flush()
+		os.Exit(1)
+	}
 
-	svc := internal.NewService(repo, logger)
+	cacheConfig, err := newCacheConfig(os.Getenv)
+	if err != nil {
+		logger.With(golog.Err(err)).Error(ctx, "cache config was not valid")
+		flusher.Flush()
+		os.Exit(1)
+	}
+
+	kind, driver := os.Getenv("ORDER_STORE"), os.Getenv("DB_DRIVER")
+	var (
+		svc    *internal.Service
+		repo   order.Repo
+		cached *cache.Cache
+	)
+	switch {
+	case kind == "memory":
+		m, err := newMemory(ctx, os.Getenv("ORDER_FIXTURE"), logger)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "order fixture was not valid")
+			flusher.Flush()
+			os.Exit(1)
+		}
+		repo, cached, err = newRepo(m, "memory", cacheConfig, logger)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "cache was not created")
+			flusher.Flush()
+			os.Exit(1)
+		}
+		svc = internal.NewService(
+			repo,
+			m,
+			order.NewMemoryNumberAllocator(formats),
+			newPublisher(false, logger),
+			logger,
+			internal.WithClock(clock),
+		)
+	case driver == "sqlite":
+		if kind == "events" {
+			logger.Error(ctx, "orders are not stored as events in sqlite")
+			flusher.Flush()
+			os.Exit(1)
+		}
+		db, err := newSQLite(ctx, os.Getenv("DB_URL"))
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "sqlite database was not opened")
+			flusher.Flush()
+			os.Exit(1)
+		}
+		s := sqlite.New(db, logger)
+		repo, cached, err = newRepo(s, "sqlite", cacheConfig, logger)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "cache was not created")
+			flusher.Flush()
+			os.Exit(1)
+		}
+		svc = internal.NewService(
+			repo,
+			s,
+			sqlite.NewNumberAllocator(db, formats, logger),
+			newPublisher(false, logger),
+			logger,
+			internal.WithClock(clock),
+		)
+	case driver == "" || driver == "postgres":
+		db := newDB(ctx, logger)
+		outbox := os.Getenv("OUTBOX_ENABLED") == "true"
+		store, err := newStore(db, kind, os.Getenv("ORDER_SNAPSHOT_EVERY"), outbox, logger)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "order store was not valid")
+			flusher.Flush()
+			os.Exit(1)
+		}
+		repo, cached, err = newRepo(store, "postgres", cacheConfig, logger)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "cache was not created")
+			flusher.Flush()
+			os.Exit(1)
+		}
+		svc = internal.NewService(
+			repo,
+			postgres.New(db, logger),
+			postgres.NewNumberAllocator(db, formats, logger),
+			newPublisher(outbox, logger),
+			logger,
+			internal.WithClock(clock),
+			internal.WithTransactor(postgres.NewTransactor(db, logger)),
+			internal.WithLocker(instrument.NewLocker(store, "postgres"), lockMode),
+		)
+	default:
+		logger.With(golog.String("driver", driver)).Error(ctx, "database driver was not valid")
+		flusher.Flush()
+		os.Exit(1)
+	}
 
 	var code int
 	switch action {
@@ -51,7 +197,19 @@ func main() {
 			code = 1
 			break
 		}
-		o, err := svc.Place(ctx, order.GenerateNumber(), uID)
+		_items, err := parseItems(items, currency)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "items were not valid")
+			code = 1
+			break
+		}
+		t, err := order.ParseTenant(tenant)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "tenant was not valid")
+			code = 1
+			break
+		}
+		o, err := svc.Place(ctx, t, uID, _items)
 		if err != nil {
 			logger.With(golog.Err(err)).Error(ctx, "order was not placed")
 			code = 2
@@ -68,7 +226,7 @@ func main() {
 		o, err := svc.MarkAsShipped(ctx, _id)
 		if err != nil {
 			logger.With(golog.Err(err)).Error(ctx, "order was not shipped")
-			code = 2
+			code = exitCode(err)
 			break
 		}
 		logger.With(golog.String("oder", fmt.Sprintf("%v", o))).Debug(ctx, "order was shipped")
@@ -82,10 +240,111 @@ func main() {
 		o, err := svc.MarkAsDelivered(ctx, _id)
 		if err != nil {
 			logger.With(golog.Err(err)).Error(ctx, "order was not delviered")
-			code = 2
+			code = exitCode(err)
 			break
 		}
 		logger.With(golog.String("oder", fmt.Sprintf("%v", o))).Debug(ctx, "order was delviered")
+	case "cancel":
+		_id, err := order.ParseID(id)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "id was not valid")
+			code = 1
+			break
+		}
+		r, err := order.ParseCancellationReason(reason)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "reason was not valid")
+			code = 1
+			break
+		}
+		o, err := svc.Cancel(ctx, _id, r)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "order was not cancelled")
+			code = exitCode(err)
+			break
+		}
+		logger.With(golog.String("oder", fmt.Sprintf("%v", o))).Debug(ctx, "order was cancelled")
+	case "change_address":
+		_id, err := order.ParseID(id)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "id was not valid")
+			code = 1
+			break
+		}
+		a, err := order.ParseAddress(recipient, strings.Split(lines, "|"), city, postalCode, country)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "address was not valid")
+			code = 1
+			break
+		}
+		o, err := svc.ChangeShippingAddress(ctx, _id, a)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "order shipping address was not changed")
+			code = exitCode(err)
+			break
+		}
+		logger.With(golog.String("oder", fmt.Sprintf("%v", o))).Debug(ctx, "order shipping address was changed")
+	case "list":
+		if kind == "events" {
+			logger.Error(ctx, "orders are not listed from the event store, which keeps no table of their latest state")
+			code = 1
+			break
+		}
+		f, err := parseFilter(placedBy, statuses, placedAt, shippedAt, deliveredAt, limit)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "filter was not valid")
+			code = 1
+			break
+		}
+		c, err := codec.Decode(cursor, f)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "cursor was not valid")
+			code = 1
+			break
+		}
+		p, err := svc.List(ctx, f, c)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "orders were not listed")
+			code = 2
+			break
+		}
+		for _, o := range p.Orders {
+			logger.With(golog.String("oder", fmt.Sprintf("%v", o))).Debug(ctx, "order was listed")
+		}
+		logger.With(
+			golog.String("next", codec.Encode(p.Next, f)),
+			golog.String("previous", codec.Encode(p.Previous, f)),
+		).Debug(ctx, "orders were listed")
+	case "evict":
+		if cached == nil || !cacheConfig.Shared() {
+			logger.Error(ctx, "orders are not cached in a shared store, enable the cache and set CACHE_DIR to evict them from the CLI")
+			code = 1
+			break
+		}
+		_id, err := order.ParseID(id)
+		if err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "id was not valid")
+			code = 1
+			break
+		}
+		if err := cached.Evict(ctx, _id); err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "order was not evicted from cache")
+			code = 2
+			break
+		}
+		logger.With(golog.String("id", _id.String())).Debug(ctx, "order was evicted from cache")
+	case "purge":
+		if cached == nil || !cacheConfig.Shared() {
+			logger.Error(ctx, "orders are not cached in a shared store, enable the cache and set CACHE_DIR to purge them from the CLI")
+			code = 1
+			break
+		}
+		if err := cached.Purge(ctx); err != nil {
+			logger.With(golog.Err(err)).Error(ctx, "cache was not purged")
+			code = 2
+			break
+		}
+		logger.Debug(ctx, "cache was purged")
 	default:
 		logger.Error(ctx, "action not valid")
 	}
@@ -113,16 +372,323 @@ func newDB(ctx context.Context, logger golog.Logger) *sql.DB {
 	return db
 }
 
-func newRepo(db *sql.DB, logger golog.Logger) order.Repo {
-	return instrument.New(
-		cache.New(
-			instrument.New(
-				postgres.New(db, logger),
-				"postgres",
-			),
-			cache.DefaultStore(),
-			logger,
-		),
-		"cache",
+// newSQLite opens the SQLite database file at path and applies the migrations it is missing
+// orders are stored in a single file on single-node deployments, so the schema is kept up to date by the CLI itself
+func newSQLite(ctx context.Context, path string) (*sql.DB, error) {
+	db, err := sqlite.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sqlite.Migrate(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// store represents the database layer the orders are stored in
+type store interface {
+	order.Repo
+	order.Locker
+}
+
+// newStore returns the database layer the orders are stored in
+// orders are stored as rows by default, or as streams of events snapshotted every few events when kind is events
+// (DefaultSnapshotEvery when empty)
+func newStore(db *sql.DB, kind, snapshotEvery string, outbox bool, logger golog.Logger) (store, error) {
+	switch kind {
+	case "", "state":
+		if outbox {
+			return postgres.NewWithOutbox(db, logger), nil
+		}
+		return postgres.New(db, logger), nil
+	case "events":
+		every := postgres.DefaultSnapshotEvery
+		if snapshotEvery != "" {
+			var err error
+			if every, err = strconv.Atoi(snapshotEvery); err != nil {
+				return nil, fmt.Errorf("could not parse snapshot frequency: %w", err)
+			}
+		}
+		if outbox {
+			return postgres.NewEventStoreWithOutbox(db, every, logger), nil
+		}
+		return postgres.NewEventStore(db, every, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown order store: %s", kind)
+	}
+}
+
+// newRepo returns the order.Repo used by the service, caching the base named after its storage when the cache is enabled
+// the cache is returned as well, so that orders can be evicted from it, nil when disabled
+func newRepo(base order.Repo, name string, config cache.Config, logger golog.Logger) (order.Repo, *cache.Cache, error) {
+	repo := instrument.New(base, name)
+	if !config.Enabled {
+		return repo, nil, nil
+	}
+
+	store, err := newCacheStore(config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := cache.New(repo, store, logger, config.Options()...)
+	return instrument.New(c, "cache"), c, nil
+}
+
+// newCacheStore returns the store the orders are cached in
+// orders are cached in files shared by the processes using the same directory when configured, in the memory of the process otherwise
+func newCacheStore(config cache.Config) (cache.Store, error) {
+	if config.Shared() {
+		return cache.NewFileStore(config.Dir)
+	}
+
+	return cache.NewStore(config), nil
+}
+
+// newCacheConfig returns the config of the cache, read from the environment using getenv
+// durations are formatted as Go durations (e.g. 90s), and the default config is used for empty values
+func newCacheConfig(getenv func(string) string) (cache.Config, error) {
+	c := cache.DefaultConfig()
+	var err error
+	if s := getenv("CACHE_ENABLED"); s != "" {
+		if c.Enabled, err = strconv.ParseBool(s); err != nil {
+			return cache.Config{}, fmt.Errorf("could not parse cache enabled: %w", err)
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"CACHE_TTL":              &c.TTL,
+		"CACHE_TTL_JITTER":       &c.Jitter,
+		"CACHE_NEGATIVE_TTL":     &c.NegativeTTL,
+		"CACHE_CLEANUP_INTERVAL": &c.CleanupInterval,
+	}
+	for key, d := range durations {
+		if s := getenv(key); s != "" {
+			if *d, err = time.ParseDuration(s); err != nil {
+				return cache.Config{}, fmt.Errorf("could not parse %s: %w", key, err)
+			}
+		}
+	}
+
+	if s := getenv("CACHE_CAPACITY"); s != "" {
+		if c.Capacity, err = strconv.Atoi(s); err != nil {
+			return cache.Config{}, fmt.Errorf("could not parse cache capacity: %w", err)
+		}
+	}
+
+	c.Dir = getenv("CACHE_DIR")
+	if s := getenv("CACHE_EARLY_REFRESH"); s != "" {
+		if c.EarlyRefresh, err = strconv.ParseFloat(s, 64); err != nil {
+			return cache.Config{}, fmt.Errorf("could not parse cache early refresh: %w", err)
+		}
+	}
+
+	return c, c.Validate()
+}
+
+// newMemory returns the in-memory layer the orders are stored in, meant for local development
+// it is seeded with the orders of the JSON fixture file at path, or left empty when no path is given
+func newMemory(ctx context.Context, path string, logger golog.Logger) (*memory.Memory, error) {
+	if path == "" {
+		return memory.New(logger), nil
+	}
+
+	return memory.NewFromFile(ctx, path, logger)
+}
+
+// newPublisher returns the publisher used by the service
+// when the outbox is enabled, events are published by the relay instead
+func newPublisher(outbox bool, logger golog.Logger) internal.Publisher {
+	if outbox {
+		return publisher.Discard{}
+	}
+
+	return publisher.NewLogger(logger)
+}
+
+// newNumberFormats returns the number format of every tenant
+// tenants are configured as comma separated tenant:prefix (e.g. web:WEB,store:STO), each using the default format with its own prefix
+// tenants and prefixes must not be repeated, so that numbers are unique across tenants
+// only the default tenant is configured when empty
+func newNumberFormats(s string) (order.NumberFormats, error) {
+	if s == "" {
+		return order.NumberFormats{"default": order.DefaultNumberFormat}, nil
+	}
+
+	formats, prefixes := order.NumberFormats{}, map[string]order.Tenant{}
+	for _, raw := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(raw), ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("tenant not formatted as tenant:prefix: %s", raw)
+		}
+
+		t, err := order.ParseTenant(parts[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := formats[t]; ok {
+			return nil, fmt.Errorf("tenant configured more than once: %s", t)
+		}
+
+		if other, ok := prefixes[parts[1]]; ok {
+			return nil, fmt.Errorf("prefix %s of tenant %s already used by tenant %s", parts[1], t, other)
+		}
+
+		f := order.DefaultNumberFormat
+		f.Prefix = parts[1]
+		formats[t], prefixes[f.Prefix] = f, t
+	}
+
+	return formats, nil
+}
+
+// placeholderCursorKey is the cursor key once shipped in .env.dist, publicly known and so never to be used
+const placeholderCursorKey = "change-me-to-a-secret-of-32-bytes-or-more"
+
+// newCursorCodec returns the codec of the cursor tokens signed with the key
+// the key is required, so that tokens are never signed with a missing or publicly known one
+func newCursorCodec(key string) (*order.CursorCodec, error) {
+	switch key {
+	case "":
+		return nil, fmt.Errorf("%w: key is missing", order.ErrCursorKeyNotValid)
+	case placeholderCursorKey:
+		return nil, fmt.Errorf("%w: key is the publicly known placeholder", order.ErrCursorKeyNotValid)
+	}
+
+	return order.NewCursorCodec([]byte(key))
+}
+
+// newLockMode returns how the use cases wait for the orders locked by someone else
+// they wait until the lock is released when empty
+func newLockMode(s string) (order.LockMode, error) {
+	if s == "" {
+		return order.LockWait, nil
+	}
+
+	return order.ParseLockMode(s)
+}
+
+// newClock returns the clock used by the service
+// when a time is given, actions run at that time, so that historical commands can be replayed
+func newClock(at string) (order.Clock, error) {
+	if at == "" {
+		return order.SystemClock{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		return nil, err
+	}
+
+	return order.NewFakeClock(t), nil
+}
+
+// exitCode returns the exit code for a failed use case
+// a conflict exits with a distinct code, so that scripts can retry the action
+func exitCode(err error) int {
+	if errors.Is(err, internal.ErrConflict) {
+		return 3
+	}
+
+	return 2
+}
+
+// parseItems parses line items formatted as comma separated sku:quantity:unit_price
+// e.g. TSHIRT-RED:2:19.99,MUG:1:8.99
+func parseItems(s, currency string) ([]order.LineItem, error) {
+	var items []order.LineItem
+	for _, raw := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(raw), ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("item not formatted as sku:quantity:unit_price: %s", raw)
+		}
+
+		sku, err := order.ParseSKU(parts[0])
+		if err != nil {
+			return nil, err
+		}
+
+		qty, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("could not parse quantity: %w", err)
+		}
+
+		price, err := order.ParseMoney(parts[2] + " " + currency)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse unit price: %w", err)
+		}
+
+		item, err := order.NewLineItem(sku, qty, price)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// timeRange represents the bounds of a time range to list orders by, as given to the CLI
+type timeRange struct {
+	from, to string
+}
+
+// parse parses the bounds formatted as RFC 3339, an empty bound leaving the range open on its side
+func (r timeRange) parse() (order.TimeRange, error) {
+	var (
+		tr  order.TimeRange
+		err error
 	)
+	if r.from != "" {
+		if tr.From, err = time.Parse(time.RFC3339, r.from); err != nil {
+			return order.TimeRange{}, err
+		}
+	}
+
+	if r.to != "" {
+		if tr.To, err = time.Parse(time.RFC3339, r.to); err != nil {
+			return order.TimeRange{}, err
+		}
+	}
+
+	return tr, nil
+}
+
+// parseFilter parses the criteria to list orders by
+// statuses are comma separated, times are formatted as RFC 3339 and empty values match any order
+func parseFilter(placedBy, statuses string, placedAt, shippedAt, deliveredAt timeRange, limit int) (order.Filter, error) {
+	f := order.Filter{Limit: limit}
+	if placedBy != "" {
+		uID, err := order.ParseUserID(placedBy)
+		if err != nil {
+			return order.Filter{}, err
+		}
+		f.PlacedBy = uID
+	}
+
+	if statuses != "" {
+		for _, raw := range strings.Split(statuses, ",") {
+			f.Statuses = append(f.Statuses, order.Status(strings.TrimSpace(raw)))
+		}
+	}
+
+	var err error
+	if f.PlacedAt, err = placedAt.parse(); err != nil {
+		return order.Filter{}, err
+	}
+
+	if f.ShippedAt, err = shippedAt.parse(); err != nil {
+		return order.Filter{}, err
+	}
+
+	if f.DeliveredAt, err = deliveredAt.parse(); err != nil {
+		return order.Filter{}, err
+	}
+
+	return f, f.Validate()
 }
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/cache"
+)
+
+func TestParseFilter(t *testing.T) {
+	from := time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	uID := order.UserID(uuid.New())
+
+	tests := map[string]struct {
+		placedBy    string
+		statuses    string
+		placedAt    timeRange
+		shippedAt   timeRange
+		deliveredAt timeRange
+		limit       int
+		want        order.Filter
+		err         error
+	}{
+		"empty": {
+			want: order.Filter{},
+		},
+		"all criteria": {
+			placedBy:    uID.String(),
+			statuses:    "shipped, delivered",
+			placedAt:    timeRange{from: from.Format(time.RFC3339), to: to.Format(time.RFC3339)},
+			shippedAt:   timeRange{from: from.Format(time.RFC3339)},
+			deliveredAt: timeRange{to: to.Format(time.RFC3339)},
+			limit:       10,
+			want: order.Filter{
+				PlacedBy:    uID,
+				Statuses:    []order.Status{order.Shipped, order.Delivered},
+				PlacedAt:    order.TimeRange{From: from, To: to},
+				ShippedAt:   order.TimeRange{From: from},
+				DeliveredAt: order.TimeRange{To: to},
+				Limit:       10,
+			},
+		},
+		"shipped range ending before it starts": {
+			shippedAt: timeRange{from: to.Format(time.RFC3339), to: from.Format(time.RFC3339)},
+			err:       order.ErrFilterNotValid,
+		},
+		"delivered range ending before it starts": {
+			deliveredAt: timeRange{from: to.Format(time.RFC3339), to: from.Format(time.RFC3339)},
+			err:         order.ErrFilterNotValid,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			f, err := parseFilter(tt.placedBy, tt.statuses, tt.placedAt, tt.shippedAt, tt.deliveredAt, tt.limit)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("could not match error: %s", err)
+			}
+
+			if tt.err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(f, tt.want) {
+				t.Error("could not match filter")
+				t.Errorf("got: %+v", f)
+				t.Errorf("want: %+v", tt.want)
+			}
+		})
+	}
+
+	t.Run("time not valid", func(t *testing.T) {
+		for _, r := range []timeRange{{from: "yesterday"}, {to: "tomorrow"}} {
+			if _, err := parseFilter("", "", timeRange{}, r, timeRange{}, 0); err == nil {
+				t.Errorf("could not match error for shipped range %+v", r)
+			}
+
+			if _, err := parseFilter("", "", timeRange{}, timeRange{}, r, 0); err == nil {
+				t.Errorf("could not match error for delivered range %+v", r)
+			}
+		}
+	})
+}
+
+func TestNewCursorCodec(t *testing.T) {
+	tests := map[string]struct {
+		key string
+		err error
+	}{
+		"secret":      {key: strings.Repeat("s", order.MinCursorKeyLength)},
+		"missing":     {err: order.ErrCursorKeyNotValid},
+		"placeholder": {key: placeholderCursorKey, err: order.ErrCursorKeyNotValid},
+		"too short":   {key: "secret", err: order.ErrCursorKeyNotValid},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			codec, err := newCursorCodec(tt.key)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("could not match error: %s", err)
+			}
+
+			if tt.err == nil && codec == nil {
+				t.Fatal("could not match codec")
+			}
+		})
+	}
+}
+
+func TestNewNumberFormats(t *testing.T) {
+	withPrefix := func(prefix string) order.NumberFormat {
+		f := order.DefaultNumberFormat
+		f.Prefix = prefix
+		return f
+	}
+
+	tests := map[string]struct {
+		s       string
+		want    order.NumberFormats
+		wantErr bool
+	}{
+		"empty": {
+			want: order.NumberFormats{"default": order.DefaultNumberFormat},
+		},
+		"tenants": {
+			s:    "default:ORD, web:WEB,store:STO",
+			want: order.NumberFormats{"default": withPrefix("ORD"), "web": withPrefix("WEB"), "store": withPrefix("STO")},
+		},
+		"not formatted": {
+			s:       "web",
+			wantErr: true,
+		},
+		"tenant repeated": {
+			s:       "web:WEB,web:WWW",
+			wantErr: true,
+		},
+		"prefix repeated": {
+			s:       "web:WEB,store:WEB",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			formats, err := newNumberFormats(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("could not match error: %v", err)
+			}
+
+			if !reflect.DeepEqual(formats, tt.want) {
+				t.Error("could not match formats")
+				t.Errorf("got: %+v", formats)
+				t.Errorf("want: %+v", tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCacheConfig(t *testing.T) {
+	tests := map[string]struct {
+		env     map[string]string
+		want    func(*cache.Config)
+		wantErr bool
+		err     error
+	}{
+		"default": {
+			want: func(*cache.Config) {},
+		},
+		"configured": {
+			env: map[string]string{
+				"CACHE_ENABLED":          "true",
+				"CACHE_TTL":              "90s",
+				"CACHE_TTL_JITTER":       "10s",
+				"CACHE_NEGATIVE_TTL":     "2s",
+				"CACHE_CAPACITY":         "500",
+				"CACHE_CLEANUP_INTERVAL": "5m",
+				"CACHE_DIR":              "/var/cache/orders",
+				"CACHE_EARLY_REFRESH":    "1.5",
+			},
+			want: func(c *cache.Config) {
+				c.TTL = 90 * time.Second
+				c.Jitter = 10 * time.Second
+				c.NegativeTTL = 2 * time.Second
+				c.Capacity = 500
+				c.CleanupInterval = 5 * time.Minute
+				c.Dir = "/var/cache/orders"
+				c.EarlyRefresh = 1.5
+			},
+		},
+		"disabled": {
+			env: map[string]string{"CACHE_ENABLED": "false", "CACHE_TTL": "0s"},
+			want: func(c *cache.Config) {
+				c.Enabled = false
+				c.TTL = 0
+			},
+		},
+		"enabled not valid": {
+			env:     map[string]string{"CACHE_ENABLED": "maybe"},
+			wantErr: true,
+			err:     strconv.ErrSyntax,
+		},
+		"ttl not valid": {
+			env:     map[string]string{"CACHE_TTL": "a minute"},
+			wantErr: true,
+		},
+		"ttl not positive": {
+			env:     map[string]string{"CACHE_TTL": "0s"},
+			wantErr: true,
+			err:     cache.ErrConfigNotValid,
+		},
+		"jitter not valid": {
+			env:     map[string]string{"CACHE_TTL_JITTER": "6"},
+			wantErr: true,
+		},
+		"jitter out of range": {
+			env:     map[string]string{"CACHE_TTL_JITTER": "-1s"},
+			wantErr: true,
+			err:     cache.ErrConfigNotValid,
+		},
+		"negative ttl not valid": {
+			env:     map[string]string{"CACHE_NEGATIVE_TTL": "soon"},
+			wantErr: true,
+		},
+		"negative ttl out of range": {
+			env:     map[string]string{"CACHE_NEGATIVE_TTL": "-5s"},
+			wantErr: true,
+			err:     cache.ErrConfigNotValid,
+		},
+		"capacity not valid": {
+			env:     map[string]string{"CACHE_CAPACITY": "lots"},
+			wantErr: true,
+			err:     strconv.ErrSyntax,
+		},
+		"capacity negative": {
+			env:     map[string]string{"CACHE_CAPACITY": "-1"},
+			wantErr: true,
+			err:     cache.ErrConfigNotValid,
+		},
+		"cleanup interval not positive": {
+			env:     map[string]string{"CACHE_CLEANUP_INTERVAL": "0s"},
+			wantErr: true,
+			err:     cache.ErrConfigNotValid,
+		},
+		"early refresh not valid": {
+			env:     map[string]string{"CACHE_EARLY_REFRESH": "early"},
+			wantErr: true,
+			err:     strconv.ErrSyntax,
+		},
+		"early refresh negative": {
+			env:     map[string]string{"CACHE_EARLY_REFRESH": "-1"},
+			wantErr: true,
+			err:     cache.ErrConfigNotValid,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := newCacheConfig(func(key string) string {
+				return tt.env[key]
+			})
+			if (err != nil) != tt.wantErr || (tt.err != nil && !errors.Is(err, tt.err)) {
+				t.Fatalf("could not match error: %v", err)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			want := cache.DefaultConfig()
+			tt.want(&want)
+			if !reflect.DeepEqual(c, want) {
+				t.Error("could not match config")
+				t.Errorf("got: %+v", c)
+				t.Errorf("want: %+v", want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/internal"
+)
+
+var (
+	_ internal.Publisher = Discard{}
+)
+
+// Discard represents a publisher dropping every event
+// It is used when events are published by the outbox relay instead
+type Discard struct{}
+
+// Publish drops the given events
+func (Discard) Publish(context.Context, ...order.Event) error {
+	return nil
+}
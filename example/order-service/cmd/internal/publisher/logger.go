@@ -0,0 +1,38 @@
+package publisher
+
+import (
+	"context"
+	"time"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/internal"
+)
+
+var (
+	_ internal.Publisher = &Logger{}
+)
+
+// Logger represents a publisher writing domain events to the logs
+// It is meant for local development, until a message broker is plugged in
+type Logger struct {
+	logger golog.Logger
+}
+
+// NewLogger returns a publisher writing domain events to the given logger
+func NewLogger(logger golog.Logger) *Logger {
+	return &Logger{logger: logger}
+}
+
+// Publish writes the given events to the logs
+func (l *Logger) Publish(ctx context.Context, events ...order.Event) error {
+	for _, e := range events {
+		l.logger.With(
+			golog.String("event", e.EventName()),
+			golog.String("aggregate_id", e.AggregateID().String()),
+			golog.String("occurred_at", e.OccurredAt().Format(time.RFC3339Nano)),
+		).Info(ctx, "event was published")
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/internal"
+)
+
+// Errors that the relay exposes
+var (
+	ErrNotDispatched = errors.New("outbox could not be dispatched")
+)
+
+// Message represents an event stored in the outbox waiting to be published
+type Message struct {
+	ID            int64
+	Event         order.Event
+	Attempts      int
+	NextAttemptAt time.Time
+}
+
+// DefaultMaxAttempts is the number of attempts after which a message failing to be published is parked
+const DefaultMaxAttempts = 10
+
+// Outbox represents the storage holding the events waiting to be published
+// Pending must return the messages not dispatched nor parked yet, in the order they were stored
+type Outbox interface {
+	Pending(ctx context.Context, limit int) ([]Message, error)
+	MarkDispatched(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, reason error) error
+	MarkParked(ctx context.Context, id int64, reason error) error
+}
+
+// Backoff returns how long to wait before publishing again a message that failed the given number of attempts
+type Backoff func(attempts int) time.Duration
+
+// ExponentialBackoff returns a Backoff doubling the wait on every attempt, starting from base and never exceeding max
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempts int) time.Duration {
+		d := base
+		for i := 1; i < attempts && d < max; i++ {
+			d *= 2
+		}
+
+		if d > max {
+			return max
+		}
+
+		return d
+	}
+}
+
+// Relay polls the outbox and publishes the stored events in order
+// Events are published at least once: a crash between publishing and marking a message as dispatched publishes it again
+// Only one relay must run against the same outbox, otherwise the publishing order is not guaranteed
+type Relay struct {
+	outbox      Outbox
+	publisher   internal.Publisher
+	backoff     Backoff
+	maxAttempts int
+	logger      golog.Logger
+}
+
+// Option represents an optional configuration of the Relay
+type Option func(*Relay)
+
+// WithMaxAttempts sets the number of attempts after which a message failing to be published is parked,
+// DefaultMaxAttempts being used by default
+// A parked message is never published again, so that a message rejected for good does not block the following ones
+func WithMaxAttempts(n int) Option {
+	return func(r *Relay) {
+		r.maxAttempts = n
+	}
+}
+
+// New returns a Relay publishing the events stored in the outbox
+func New(outbox Outbox, publisher internal.Publisher, backoff Backoff, logger golog.Logger, opts ...Option) *Relay {
+	r := &Relay{
+		outbox:      outbox,
+		publisher:   publisher,
+		backoff:     backoff,
+		maxAttempts: DefaultMaxAttempts,
+		logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run dispatches the outbox every interval until the context is done
+func (r *Relay) Run(ctx context.Context, interval time.Duration, batchSize int) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.Dispatch(ctx, batchSize); err != nil {
+			r.logger.With(golog.Err(err)).Error(ctx, "outbox was not dispatched")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch publishes up to batchSize pending messages and returns how many were dispatched
+// It stops at the first message failing or waiting for a retry, so that the publishing order is preserved,
+// unless the message failed its last attempt and is parked, the following ones being published past it
+func (r *Relay) Dispatch(ctx context.Context, batchSize int) (int, error) {
+	msgs, err := r.outbox.Pending(ctx, batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrNotDispatched, err)
+	}
+
+	var dispatched int
+	for _, m := range msgs {
+		// the outbox stores times in UTC
+		now := time.Now().UTC()
+		if m.NextAttemptAt.After(now) {
+			r.logger.With(golog.Int64("message_id", m.ID)).Debug(ctx, "outbox message is waiting for a retry")
+			return dispatched, nil
+		}
+
+		if err := r.publisher.Publish(ctx, m.Event); err != nil {
+			if m.Attempts+1 >= r.maxAttempts {
+				if err := r.outbox.MarkParked(ctx, m.ID, err); err != nil {
+					return dispatched, fmt.Errorf("%w: %w", ErrNotDispatched, err)
+				}
+				r.logger.With(golog.Err(err), golog.Int64("message_id", m.ID)).Warn(ctx, "outbox message was parked after its last attempt")
+				continue
+			}
+
+			next := now.Add(r.backoff(m.Attempts + 1))
+			if err := r.outbox.MarkFailed(ctx, m.ID, next, err); err != nil {
+				r.logger.With(golog.Err(err), golog.Int64("message_id", m.ID)).Error(ctx, "outbox message was not marked as failed")
+			}
+			return dispatched, fmt.Errorf("%w: %w", ErrNotDispatched, err)
+		}
+
+		if err := r.outbox.MarkDispatched(ctx, m.ID); err != nil {
+			return dispatched, fmt.Errorf("%w: %w", ErrNotDispatched, err)
+		}
+
+		dispatched++
+	}
+
+	return dispatched, nil
+}
@@ -0,0 +1,267 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gologTest "github.com/damianopetrungaro/golog/test"
+	"github.com/golang/mock/gomock"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/internal"
+)
+
+func TestRelay_Dispatch(t *testing.T) {
+	t.Run("dispatched in order", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		outbox := newOutboxHelper(t, 3)
+		publisher := internal.NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		gomock.InOrder(
+			publisher.EXPECT().Publish(ctx, outbox.msgs[0].Event).Return(nil),
+			publisher.EXPECT().Publish(ctx, outbox.msgs[1].Event).Return(nil),
+			publisher.EXPECT().Publish(ctx, outbox.msgs[2].Event).Return(nil),
+		)
+
+		r := New(outbox, publisher, ExponentialBackoff(time.Second, time.Minute), logger)
+
+		n, err := r.Dispatch(ctx, 10)
+		if err != nil {
+			t.Fatalf("could not dispatch outbox: %s", err)
+		}
+
+		if n != 3 {
+			t.Errorf("could not match dispatched messages: %d", n)
+		}
+
+		if len(outbox.dispatched) != 3 {
+			t.Errorf("could not match messages marked as dispatched: %v", outbox.dispatched)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		outbox := newOutboxHelper(t, 3)
+		publisher := internal.NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		gomock.InOrder(
+			publisher.EXPECT().Publish(ctx, outbox.msgs[0].Event).Return(nil),
+			publisher.EXPECT().Publish(ctx, outbox.msgs[1].Event).Return(errors.New("broker unavailable")),
+		)
+
+		r := New(outbox, publisher, ExponentialBackoff(time.Second, time.Minute), logger)
+
+		n, err := r.Dispatch(ctx, 10)
+		if !errors.Is(err, ErrNotDispatched) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		if n != 1 {
+			t.Errorf("could not match dispatched messages: %d", n)
+		}
+
+		m := outbox.msgs[1]
+		if m.Attempts != 1 {
+			t.Errorf("could not match failed attempts: %d", m.Attempts)
+		}
+
+		if time.Until(m.NextAttemptAt) < 500*time.Millisecond {
+			t.Errorf("could not match next attempt scheduled with backoff: %s", m.NextAttemptAt)
+		}
+
+		// the failed message blocks the following ones until it is due
+		n, err = r.Dispatch(ctx, 10)
+		if err != nil {
+			t.Fatalf("could not dispatch outbox: %s", err)
+		}
+
+		if n != 0 {
+			t.Errorf("could not match dispatched messages while waiting for a retry: %d", n)
+		}
+	})
+}
+
+func TestRelay_Dispatch_Parked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	ctx := context.Background()
+	outbox := newOutboxHelper(t, 3)
+	publisher := internal.NewMockPublisher(ctrl)
+	logger := gologTest.NewNullLogger()
+
+	// the broker rejects the first message for good
+	errRejected := errors.New("message rejected")
+	gomock.InOrder(
+		publisher.EXPECT().Publish(ctx, outbox.msgs[0].Event).Times(3).Return(errRejected),
+		publisher.EXPECT().Publish(ctx, outbox.msgs[1].Event).Return(nil),
+		publisher.EXPECT().Publish(ctx, outbox.msgs[2].Event).Return(nil),
+	)
+
+	r := New(outbox, publisher, func(int) time.Duration { return 0 }, logger, WithMaxAttempts(3))
+
+	for i := 0; i < 2; i++ {
+		n, err := r.Dispatch(ctx, 10)
+		if !errors.Is(err, ErrNotDispatched) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		if n != 0 {
+			t.Fatalf("could not match messages dispatched past the failed one: %d", n)
+		}
+	}
+
+	// the last attempt parks the message and dispatches the following ones
+	n, err := r.Dispatch(ctx, 10)
+	if err != nil {
+		t.Fatalf("could not dispatch outbox: %s", err)
+	}
+
+	if n != 2 {
+		t.Errorf("could not match dispatched messages: %d", n)
+	}
+
+	if len(outbox.parked) != 1 || outbox.parked[0] != outbox.msgs[0].ID {
+		t.Errorf("could not match parked messages: %v", outbox.parked)
+	}
+
+	// the parked message is never published again
+	n, err = r.Dispatch(ctx, 10)
+	if err != nil {
+		t.Fatalf("could not dispatch outbox: %s", err)
+	}
+
+	if n != 0 {
+		t.Errorf("could not match dispatched messages once parked: %d", n)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 10*time.Second)
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 4, want: 8 * time.Second},
+		{attempts: 5, want: 10 * time.Second},
+		{attempts: 50, want: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != tt.want {
+			t.Errorf("could not match backoff for %d attempts", tt.attempts)
+			t.Errorf("got: %s", got)
+			t.Errorf("want: %s", tt.want)
+		}
+	}
+}
+
+type outboxHelper struct {
+	mu         sync.Mutex
+	msgs       []Message
+	dispatched []int64
+	parked     []int64
+}
+
+func newOutboxHelper(t *testing.T, n int) *outboxHelper {
+	t.Helper()
+
+	id := order.NewID()
+	h := &outboxHelper{}
+	for i := 0; i < n; i++ {
+		h.msgs = append(h.msgs, Message{
+			ID:    int64(i + 1),
+			Event: order.OrderShipped{OrderID: id, At: time.Now().Add(time.Duration(i) * time.Second)},
+		})
+	}
+
+	return h
+}
+
+func (h *outboxHelper) Pending(_ context.Context, limit int) ([]Message, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var msgs []Message
+	for _, m := range h.msgs {
+		if h.isDispatched(m.ID) || h.isParked(m.ID) {
+			continue
+		}
+		msgs = append(msgs, m)
+		if len(msgs) == limit {
+			break
+		}
+	}
+
+	return msgs, nil
+}
+
+func (h *outboxHelper) MarkDispatched(_ context.Context, id int64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.dispatched = append(h.dispatched, id)
+	return nil
+}
+
+func (h *outboxHelper) MarkFailed(_ context.Context, id int64, nextAttemptAt time.Time, _ error) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i := range h.msgs {
+		if h.msgs[i].ID == id {
+			h.msgs[i].Attempts++
+			h.msgs[i].NextAttemptAt = nextAttemptAt
+		}
+	}
+
+	return nil
+}
+
+func (h *outboxHelper) MarkParked(_ context.Context, id int64, _ error) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.parked = append(h.parked, id)
+	return nil
+}
+
+func (h *outboxHelper) isParked(id int64) bool {
+	for _, p := range h.parked {
+		if p == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *outboxHelper) isDispatched(id int64) bool {
+	for _, d := range h.dispatched {
+		if d == id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -2,74 +2,425 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"github.com/damianopetrungaro/go-cache"
 	"github.com/damianopetrungaro/golog"
 	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/transaction"
+	"golang.org/x/sync/singleflight"
+	"math"
+	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var (
 	_ order.Repo = &Cache{}
 
-	// memory represents the default in-memory cache
-	memory = cache.NewInMemory[order.ID, *order.Order](time.Minute, 10_000)
-
-	// defaultTTL represent the TTL for an item in the cache
-	defaultTTL = time.Minute
+	// errPurged is returned for an entry set before the cache was purged
+	errPurged = errors.New("cache entry was purged")
 )
 
+// Entry represents an order set in the cache
+type Entry struct {
+	// Order is nil when the order was not found in the base, so that looking it up again does not reach the base
+	// It is a snapshot of the order that must not be changed, copies of it being handed out instead
+	Order *order.Order
+	// ExpiresAt is the time the entry expires at
+	ExpiresAt time.Time
+	// Delta is the time it took to load the order from the base, the longer the earlier the entry may be refreshed
+	Delta time.Duration
+	// Generation is the number of times the cache was purged when the order was read, older entries being ignored
+	Generation uint64
+}
+
+// Store represents the storage of the entries of the Cache, such as the in-memory one returned by NewStore
+type Store = cache.Cache[order.ID, *Entry]
+
+// purger is implemented by the stores shared by several processes, which are purged for all of them at once
+type purger interface {
+	Purge(ctx context.Context) error
+}
+
+// coalescer coalesces the concurrent calls of the same key into one, as singleflight.Group does
+type coalescer interface {
+	DoChan(key string, fn func() (any, error)) <-chan singleflight.Result
+}
+
 // Cache represents a cache layer for the order.Repo
+// Concurrent misses of the same order are coalesced into a single load from the base,
+// and orders not found are remembered for a shorter time than the ones found
+// Orders are stored as snapshots and handed out as copies, so that the changes of a caller never reach the cache
+// unless they are added
 type Cache struct {
-	base   order.Repo
-	store  cache.Cache[order.ID, *order.Order]
-	logger golog.Logger
+	base        order.Repo
+	store       Store
+	logger      golog.Logger
+	loads       coalescer
+	ttl         time.Duration
+	jitter      time.Duration
+	negativeTTL time.Duration
+	loadTimeout time.Duration
+	beta        float64
+	random      func() float64
+	generation  atomic.Uint64
+	// writes counts the orders set by Add or evicted, so that an order loaded before is not set over them
+	writes atomic.Uint64
+	// mu serializes the writes with the orders loaded
+	mu sync.Mutex
 }
 
-// DefaultStore returns a default cache store
-func DefaultStore() cache.Cache[order.ID, *order.Order] {
-	return memory
+// Option represents an optional configuration of the Cache
+type Option func(*Cache)
+
+// WithTTL sets the time orders are cached for, plus a random time up to jitter, DefaultTTL and no jitter being used by default
+// The jitter spreads the expiration of the orders cached together, so that they are not all loaded again at once
+func WithTTL(ttl, jitter time.Duration) Option {
+	return func(c *Cache) {
+		c.ttl = ttl
+		c.jitter = jitter
+	}
 }
 
-// New returns a cache wrapper for an order.Repo
-func New(base order.Repo, store cache.Cache[order.ID, *order.Order], logger golog.Logger) *Cache {
-	return &Cache{base: base, store: store, logger: logger}
+// WithNegativeTTL sets the time orders not found are remembered for, DefaultNegativeTTL being used by default
+// It is meant to be shorter than the TTL, so that a missing order shows up soon once added elsewhere,
+// orders not found are not remembered when zero
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(c *Cache) {
+		c.negativeTTL = ttl
+	}
+}
+
+// WithLoadTimeout sets the time a load from the base shared by the concurrent Get may take, DefaultLoadTimeout being used by default
+// The shared load outlives the context of the Get that started it, so that its cancellation does not fail the others
+func WithLoadTimeout(timeout time.Duration) Option {
+	return func(c *Cache) {
+		c.loadTimeout = timeout
+	}
+}
+
+// WithEarlyRefresh enables the probabilistic early refresh of the entries (XFetch)
+// An entry is loaded again before it expires with a probability growing as it gets closer to its expiration,
+// so that a hot order is refreshed by a single Get instead of expiring for all of them at once
+// beta scales how early entries are refreshed, 1 being a good default, entries are refreshed once expired by default
+func WithEarlyRefresh(beta float64) Option {
+	return func(c *Cache) {
+		c.beta = beta
+	}
+}
+
+// New returns a cache wrapper for an order.Repo, storing the orders in the given store (see NewStore and NewFileStore)
+func New(base order.Repo, store Store, logger golog.Logger, opts ...Option) *Cache {
+	c := &Cache{
+		base:        base,
+		store:       store,
+		logger:      logger,
+		loads:       &singleflight.Group{},
+		ttl:         DefaultTTL,
+		negativeTTL: DefaultNegativeTTL,
+		loadTimeout: DefaultLoadTimeout,
+		random:      rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 // Get tries getting an order from the cache storage first
-// if not found calls the Find method of the base
+// if not found, loads it from the base, and if due to be refreshed early, hands it out while loading it in the background
+// An order remembered as not found returns order.ErrNotFound without reaching the base
 func (c *Cache) Get(ctx context.Context, id order.ID) (*order.Order, error) {
-	o, err := c.store.Get(ctx, id)
-	switch err {
-	case nil:
-		c.logger.With(golog.Err(err)).Debug(ctx, "order was found in cache")
-		return o, nil
+	e, err := c.store.Get(ctx, id)
+	if err == nil && e.Generation != c.generation.Load() {
+		err = errPurged
+	}
+
+	switch {
+	case err == nil && e.Order == nil:
+		c.logger.Debug(ctx, "order was found in cache as not found")
+		lookupsCounterVec.WithLabelValues(negativeHitResult).Inc()
+		return nil, order.ErrNotFound
+	case err == nil && !c.refreshEarly(e):
+		c.logger.Debug(ctx, "order was found in cache")
+		lookupsCounterVec.WithLabelValues(hitResult).Inc()
+		return e.Order.Clone(), nil
+	case err == nil:
+		c.logger.Debug(ctx, "order was found in cache and is refreshed early")
+		lookupsCounterVec.WithLabelValues(hitResult).Inc()
+		earlyRefreshesCounter.Inc()
+		c.refresh(ctx, id)
+		return e.Order.Clone(), nil
 	default:
 		c.logger.With(golog.Err(err)).Debug(ctx, "order was not found in cache")
-		o, err := c.base.Get(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		c.sets(ctx, o)
-		return o, nil
+		lookupsCounterVec.WithLabelValues(missResult).Inc()
 	}
+
+	return c.load(ctx, id)
 }
 
-// Add sets an order in the cache if successfully added
+// Add sets an order in the cache if successfully added, replacing the entry of an order remembered as not found
+// Within a transaction, the order is set once the transaction is committed, and evicted if the transaction fails to commit
+// When the order is not added, it is evicted unless the order was known to be left as it was stored,
+// since the write may have happened anyway or the order may have been changed by someone else
 func (c *Cache) Add(ctx context.Context, o *order.Order) error {
+	gen := c.generation.Load()
 	if err := c.base.Add(ctx, o); err != nil {
 		c.logger.With(golog.Err(err)).Warn(ctx, "order was not added in cache")
+		if !errors.Is(err, order.ErrDuplicateNumber) {
+			c.evict(ctx, o.ID, writeFailureReason)
+		}
+		return err
+	}
+
+	snapshot := o.Clone()
+	transaction.AfterCommit(ctx, func(ctx context.Context) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		c.writes.Add(1)
+		c.set(ctx, snapshot, 0, gen)
+	})
+	// a failed commit may have stored the order anyway, so the cached one cannot be trusted either way
+	transaction.AfterRollback(ctx, func(ctx context.Context) {
+		c.evict(ctx, o.ID, writeFailureReason)
+	})
+
+	return nil
+}
+
+// Evict removes the order from the cache, so that it is read from the base on the next Get
+// The order is evicted for every process sharing the store, only for the current one when the store is in its memory
+// A load started before is not set once the order is evicted, as for a write
+func (c *Cache) Evict(ctx context.Context, id order.ID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writes.Add(1)
+	if err := c.store.Delete(ctx, id); err != nil {
+		c.logger.With(golog.Err(err)).Error(ctx, "order was not evicted from cache")
 		return err
 	}
 
-	c.sets(ctx, o)
+	c.logger.Info(ctx, "order was evicted from cache")
+	evictionsCounterVec.WithLabelValues(evictReason).Inc()
+	return nil
+}
+
+// Purge removes all the orders from the cache, so that they are read from the base on their next Get
+// A store shared by several processes is purged for all of them, as its entries were set by any of them
+// Otherwise the entries are left for the store to expire, Get ignoring the ones set before the cache was purged
+func (c *Cache) Purge(ctx context.Context) error {
+	if err := c.purge(ctx); err != nil {
+		c.logger.With(golog.Err(err)).Error(ctx, "cache was not purged")
+		return err
+	}
 
+	c.logger.Info(ctx, "cache was purged")
+	evictionsCounterVec.WithLabelValues(purgeReason).Inc()
 	return nil
 }
 
-func (c *Cache) sets(ctx context.Context, o *order.Order) {
-	if err := c.store.Set(ctx, o.ID, o, defaultTTL); err != nil {
+// purge removes all the orders from a shared store, the loads started before not being set once it is purged,
+// or moves the cache to a new generation otherwise
+func (c *Cache) purge(ctx context.Context) error {
+	p, ok := c.store.(purger)
+	if !ok {
+		c.generation.Add(1)
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writes.Add(1)
+	return p.Purge(ctx)
+}
+
+// evict removes the order from the cache for the given reason, a failure being only logged
+func (c *Cache) evict(ctx context.Context, id order.ID, reason string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.writes.Add(1)
+	if err := c.store.Delete(ctx, id); err != nil {
+		c.logger.With(golog.Err(err)).Warn(ctx, "order was not evicted from cache")
+		return
+	}
+
+	c.logger.Debug(ctx, "order was evicted from cache")
+	evictionsCounterVec.WithLabelValues(reason).Inc()
+}
+
+// load reads the order from the base and sets it in the cache
+// Outside a transaction, concurrent loads of the same order are coalesced, the callers sharing the order and the error.
+// The shared load is detached from the context of the caller starting it and bounded by the load timeout instead,
+// every caller waiting on it until its own context is done. Within a transaction, the order is read on its own, since it may see changes
+// and locks no one else does, and is set once the transaction is committed
+// The order is set, or remembered as not found, unless an order was written meanwhile, other errors are not
+func (c *Cache) load(ctx context.Context, id order.ID) (*order.Order, error) {
+	gen, writes := c.generation.Load(), c.writes.Load()
+	if transaction.InTransaction(ctx) {
+		start := time.Now()
+		o, err := c.base.Get(ctx, id)
+		if errors.Is(err, order.ErrNotFound) {
+			transaction.AfterCommit(ctx, func(ctx context.Context) {
+				c.setsNotFound(ctx, id, gen, writes)
+			})
+		}
+		if err != nil {
+			return nil, err
+		}
+		// the order is set as it was read, whatever the caller changes until the transaction is committed
+		snapshot, delta := o.Clone(), time.Since(start)
+		transaction.AfterCommit(ctx, func(ctx context.Context) {
+			c.sets(ctx, snapshot, delta, gen, writes)
+		})
+		return o, nil
+	}
+
+	var loaded bool
+	ch := c.loads.DoChan(id.String(), func() (any, error) {
+		loaded = true
+		loadsCounterVec.WithLabelValues(loadedResult).Inc()
+
+		ctx, cancel := context.WithTimeout(detached{ctx}, c.loadTimeout)
+		defer cancel()
+
+		start := time.Now()
+		o, err := c.base.Get(ctx, id)
+		if errors.Is(err, order.ErrNotFound) {
+			c.setsNotFound(ctx, id, gen, writes)
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.sets(ctx, o, time.Since(start), gen, writes)
+		return o, nil
+	})
+	var r singleflight.Result
+	select {
+	case <-ctx.Done():
+		c.logger.With(golog.Err(ctx.Err())).Debug(ctx, "order load was given up")
+		return nil, ctx.Err()
+	case r = <-ch:
+	}
+
+	v, err := r.Val, r.Err
+	if !loaded {
+		c.logger.Debug(ctx, "order load was deduplicated")
+		loadsCounterVec.WithLabelValues(deduplicatedResult).Inc()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the order is shared by the coalesced callers
+	return v.(*order.Order).Clone(), nil
+}
+
+// refresh loads the order in the background, the entry still being valid meanwhile
+// Within a transaction, the entry is left for a Get outside of it to refresh, as the transaction may be done before the load
+// A failure is only logged, the order being loaded again once the entry expires
+func (c *Cache) refresh(ctx context.Context, id order.ID) {
+	if transaction.InTransaction(ctx) {
+		c.logger.Debug(ctx, "order was not refreshed early within a transaction")
+		return
+	}
+
+	ctx = detached{ctx}
+	go func() {
+		if _, err := c.load(ctx, id); err != nil {
+			c.logger.With(golog.Err(err)).Warn(ctx, "order was not refreshed early")
+		}
+	}()
+}
+
+// refreshEarly reports whether the entry is due to be refreshed before it expires
+// it is given by XFetch, the probability growing with the time it took to load the order and the closer its expiration
+func (c *Cache) refreshEarly(e *Entry) bool {
+	if c.beta <= 0 {
+		return false
+	}
+
+	// 1 - random is in (0, 1], so that its logarithm is finite and not positive
+	early := time.Duration(float64(e.Delta) * c.beta * -math.Log(1-c.random()))
+	return !time.Now().Add(early).Before(e.ExpiresAt)
+}
+
+// sets sets the order as read, unless orders were written since the given number of writes
+// A concurrent Add may have stored and set a newer version of the order after it was read, which must not be overwritten
+func (c *Cache) sets(ctx context.Context, o *order.Order, delta time.Duration, gen, writes uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.writes.Load() != writes {
+		c.logger.Debug(ctx, "order was not set in cache, since orders were written meanwhile")
+		return
+	}
+
+	c.set(ctx, o, delta, gen)
+}
+
+// set sets the order as read in the given generation, so that an order read before the cache was purged is ignored
+// It is called holding mu
+func (c *Cache) set(ctx context.Context, o *order.Order, delta time.Duration, gen uint64) {
+	ttl := c.ttl + time.Duration(float64(c.jitter)*c.random())
+	e := &Entry{Order: o.Clone(), ExpiresAt: time.Now().Add(ttl), Delta: delta, Generation: gen}
+	if err := c.store.Set(ctx, o.ID, e, ttl); err != nil {
 		c.logger.With(golog.Err(err)).Warn(ctx, "order was not set in cache")
+		// the previous entry, such as the order remembered as not found, must not outlive the change
+		if err := c.store.Delete(ctx, o.ID); err != nil {
+			c.logger.With(golog.Err(err)).Warn(ctx, "order was not deleted from cache")
+		}
+		return
 	}
 
 	c.logger.Info(ctx, "order was set in cache")
 }
+
+// setsNotFound remembers the order as not found, unless orders were written since the given number of writes
+// A concurrent Add may have stored and set the order after it was read as not found, which must not be overwritten
+func (c *Cache) setsNotFound(ctx context.Context, id order.ID, gen, writes uint64) {
+	if c.negativeTTL <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.writes.Load() != writes {
+		c.logger.Debug(ctx, "order was not set in cache as not found, since orders were written meanwhile")
+		return
+	}
+
+	e := &Entry{ExpiresAt: time.Now().Add(c.negativeTTL), Generation: gen}
+	if err := c.store.Set(ctx, id, e, c.negativeTTL); err != nil {
+		c.logger.With(golog.Err(err)).Warn(ctx, "order was not set in cache as not found")
+		return
+	}
+
+	c.logger.Debug(ctx, "order was set in cache as not found")
+}
+
+// detached represents a context carrying the values of its parent, but neither its deadline nor its cancellation
+type detached struct {
+	context.Context
+}
+
+// Deadline returns no deadline
+func (detached) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done returns a nil channel, as the context is never cancelled
+func (detached) Done() <-chan struct{} {
+	return nil
+}
+
+// Err returns nil, as the context is never cancelled
+func (detached) Err() error {
+	return nil
+}
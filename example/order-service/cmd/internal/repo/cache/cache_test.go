@@ -3,13 +3,30 @@ package cache
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/damianopetrungaro/go-cache"
 	gologTest "github.com/damianopetrungaro/golog/test"
 	"github.com/golang/mock/gomock"
 	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/memory"
+	"github.com/organization/order-service/cmd/internal/repo/repotest"
+	"github.com/organization/order-service/cmd/internal/repo/transaction"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"golang.org/x/sync/singleflight"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 )
 
+func TestCache_Contract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) order.Repo {
+		logger := gologTest.NewNullLogger()
+		return New(memory.New(logger), getStore(t), logger)
+	})
+}
+
 func TestCache_Add(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -25,7 +42,7 @@ func TestCache_Add(t *testing.T) {
 
 		repo.EXPECT().Add(ctx, o).Times(1).Return(nil)
 
-		cachedRepo := New(repo, DefaultStore(), logger)
+		cachedRepo := New(repo, getStore(t), logger)
 
 		if err := cachedRepo.Add(ctx, o); err != nil {
 			t.Fatalf("could not add: %s", err)
@@ -36,13 +53,84 @@ func TestCache_Add(t *testing.T) {
 			t.Fatalf("could not find order in the store: %s", err)
 		}
 
-		if found != o {
+		if !reflect.DeepEqual(found.Order, o) {
 			t.Error("could not match orders")
-			t.Errorf("got: %v", found)
+			t.Errorf("got: %v", found.Order)
+			t.Errorf("want: %v", o)
+		}
+	})
+
+	t.Run("within transaction", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx, hooks := transaction.WithHooks(context.Background())
+		o := &order.Order{ID: order.NewID()}
+
+		repo := order.NewMockRepo(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		repo.EXPECT().Add(ctx, o).Times(1).Return(nil)
+
+		cachedRepo := New(repo, getStore(t), logger)
+
+		if err := cachedRepo.Add(ctx, o); err != nil {
+			t.Fatalf("could not add: %s", err)
+		}
+
+		if _, err := cachedRepo.store.Get(ctx, o.ID); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("could find order in the store before commit: %s", err)
+		}
+
+		hooks.Run(context.Background())
+
+		found, err := cachedRepo.store.Get(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("could not find order in the store: %s", err)
+		}
+
+		if !reflect.DeepEqual(found.Order, o) {
+			t.Error("could not match orders")
+			t.Errorf("got: %v", found.Order)
 			t.Errorf("want: %v", o)
 		}
 	})
 
+	t.Run("within transaction not committed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx, hooks := transaction.WithHooks(context.Background())
+		o := &order.Order{ID: order.NewID()}
+
+		repo := order.NewMockRepo(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		repo.EXPECT().Add(ctx, o).Times(1).Return(nil)
+
+		cachedRepo := New(repo, getStore(t), logger)
+
+		stale := &Entry{Order: &order.Order{ID: o.ID}}
+		if err := cachedRepo.store.Set(ctx, o.ID, stale, time.Minute); err != nil {
+			t.Fatalf("could not set order in the store: %s", err)
+		}
+
+		if err := cachedRepo.Add(ctx, o); err != nil {
+			t.Fatalf("could not add: %s", err)
+		}
+
+		// the commit failed, so that the order may or may not have been stored
+		hooks.Rollback(context.Background())
+
+		if _, err := cachedRepo.store.Get(ctx, o.ID); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("could find order in the store once the commit failed: %s", err)
+		}
+	})
+
 	t.Run("failure", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		t.Cleanup(func() {
@@ -57,7 +145,7 @@ func TestCache_Add(t *testing.T) {
 
 		repo.EXPECT().Add(ctx, o).Times(1).Return(order.ErrNotAdded)
 
-		cachedRepo := New(repo, DefaultStore(), logger)
+		cachedRepo := New(repo, getStore(t), logger)
 
 		if err := cachedRepo.Add(ctx, o); !errors.Is(err, order.ErrNotAdded) {
 			t.Fatalf("could not match error: %s", err)
@@ -83,16 +171,16 @@ func TestCache_Get(t *testing.T) {
 		repo := order.NewMockRepo(ctrl)
 		logger := gologTest.NewNullLogger()
 
-		repo.EXPECT().Find(ctx, o.ID).Times(1).Return(o, nil)
+		repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).Return(o, nil)
 
-		cachedRepo := New(repo, DefaultStore(), logger)
+		cachedRepo := New(repo, getStore(t), logger)
 
 		found, err := cachedRepo.Get(ctx, o.ID)
 		if err != nil {
 			t.Fatalf("could not find order: %s", err)
 		}
 
-		if found != o {
+		if !reflect.DeepEqual(found, o) {
 			t.Error("could not match orders")
 			t.Errorf("got: %v", found)
 			t.Errorf("want: %v", o)
@@ -103,7 +191,7 @@ func TestCache_Get(t *testing.T) {
 			t.Fatalf("could not find order: %s", err)
 		}
 
-		if found != o {
+		if !reflect.DeepEqual(found, o) {
 			t.Error("could not match orders")
 			t.Errorf("got: %v", found)
 			t.Errorf("want: %v", o)
@@ -116,15 +204,43 @@ func TestCache_Get(t *testing.T) {
 			ctrl.Finish()
 		})
 
+		ctx := context.Background()
+		o := &order.Order{ID: order.NewID()}
+		errGet := errors.New("connection refused")
+
+		repo := order.NewMockRepo(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		repo.EXPECT().Find(gomock.Any(), o.ID).Times(2).Return(nil, errGet)
+
+		cachedRepo := New(repo, getStore(t), logger)
+
+		_, err := cachedRepo.Get(ctx, o.ID)
+		if !errors.Is(err, errGet) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		_, err = cachedRepo.Get(ctx, o.ID)
+		if !errors.Is(err, errGet) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
 		ctx := context.Background()
 		o := &order.Order{ID: order.NewID()}
 
 		repo := order.NewMockRepo(ctrl)
 		logger := gologTest.NewNullLogger()
 
-		repo.EXPECT().Find(ctx, o.ID).Times(2).Return(nil, order.ErrNotFound)
+		repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).Return(nil, order.ErrNotFound)
 
-		cachedRepo := New(repo, DefaultStore(), logger)
+		cachedRepo := New(repo, getStore(t), logger)
 
 		_, err := cachedRepo.Get(ctx, o.ID)
 		if !errors.Is(err, order.ErrNotFound) {
@@ -136,4 +252,760 @@ func TestCache_Get(t *testing.T) {
 			t.Fatalf("could find order: %s", err)
 		}
 	})
+
+	t.Run("not found then added", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		o := &order.Order{ID: order.NewID()}
+
+		repo := order.NewMockRepo(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).Return(nil, order.ErrNotFound)
+		repo.EXPECT().Add(ctx, o).Times(1).Return(nil)
+
+		cachedRepo := New(repo, getStore(t), logger)
+
+		if _, err := cachedRepo.Get(ctx, o.ID); !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("could find order: %s", err)
+		}
+
+		if err := cachedRepo.Add(ctx, o); err != nil {
+			t.Fatalf("could not add: %s", err)
+		}
+
+		found, err := cachedRepo.Get(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("could not find order: %s", err)
+		}
+
+		if !reflect.DeepEqual(found, o) {
+			t.Error("could not match orders")
+			t.Errorf("got: %v", found)
+			t.Errorf("want: %v", o)
+		}
+	})
+
+	t.Run("not found within transaction", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx, hooks := transaction.WithHooks(context.Background())
+		o := &order.Order{ID: order.NewID()}
+
+		repo := order.NewMockRepo(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).Return(nil, order.ErrNotFound)
+
+		cachedRepo := New(repo, getStore(t), logger)
+
+		if _, err := cachedRepo.Get(ctx, o.ID); !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("could find order: %s", err)
+		}
+
+		if _, err := cachedRepo.store.Get(ctx, o.ID); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("could find order in the store before commit: %s", err)
+		}
+
+		hooks.Run(context.Background())
+
+		e, err := cachedRepo.store.Get(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("could not find order in the store: %s", err)
+		}
+
+		if e.Order != nil {
+			t.Fatalf("could not match order remembered as not found: %v", e.Order)
+		}
+	})
+}
+
+func TestCache_Get_NotFoundConcurrentAdd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	ctx := context.Background()
+	o := &order.Order{ID: order.NewID()}
+
+	repo := order.NewMockRepo(ctrl)
+	logger := gologTest.NewNullLogger()
+
+	read, release := make(chan struct{}), make(chan struct{})
+	repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).DoAndReturn(func(context.Context, order.ID) (*order.Order, error) {
+		close(read)
+		<-release
+		return nil, order.ErrNotFound
+	})
+	repo.EXPECT().Add(ctx, o).Times(1).Return(nil)
+
+	cachedRepo := New(repo, getStore(t), logger)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := cachedRepo.Get(ctx, o.ID)
+		errs <- err
+	}()
+
+	// the order is added once read as not found, and before the miss is set in the cache
+	<-read
+	if err := cachedRepo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add: %s", err)
+	}
+	close(release)
+
+	if err := <-errs; !errors.Is(err, order.ErrNotFound) {
+		t.Fatalf("could not match error: %s", err)
+	}
+
+	found, err := cachedRepo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not find order added concurrently: %s", err)
+	}
+
+	if !reflect.DeepEqual(found, o) {
+		t.Error("could not match orders")
+		t.Errorf("got: %v", found)
+		t.Errorf("want: %v", o)
+	}
+}
+
+func TestCache_Get_StaleConcurrentAdd(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	ctx := context.Background()
+	stale := &order.Order{ID: order.NewID(), Version: 1}
+	o := &order.Order{ID: stale.ID, Version: 2}
+
+	repo := order.NewMockRepo(ctrl)
+	logger := gologTest.NewNullLogger()
+
+	read, release := make(chan struct{}), make(chan struct{})
+	repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).DoAndReturn(func(context.Context, order.ID) (*order.Order, error) {
+		close(read)
+		<-release
+		return stale, nil
+	})
+	repo.EXPECT().Add(ctx, o).Times(1).Return(nil)
+
+	cachedRepo := New(repo, getStore(t), logger)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := cachedRepo.Get(ctx, o.ID)
+		errs <- err
+	}()
+
+	// a newer version is added once the order is read, and before the order read is set in the cache
+	<-read
+	if err := cachedRepo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add: %s", err)
+	}
+	close(release)
+
+	if err := <-errs; err != nil {
+		t.Fatalf("could not find order: %s", err)
+	}
+
+	found, err := cachedRepo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not find order added concurrently: %s", err)
+	}
+
+	if !reflect.DeepEqual(found, o) {
+		t.Error("could not match orders")
+		t.Errorf("got: %v", found)
+		t.Errorf("want: %v", o)
+	}
+}
+
+func TestCache_Get_CallerCancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	o := &order.Order{ID: order.NewID()}
+
+	repo := order.NewMockRepo(ctrl)
+	logger := gologTest.NewNullLogger()
+
+	read, release, loadErrs := make(chan struct{}), make(chan struct{}), make(chan error, 1)
+	repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).DoAndReturn(func(ctx context.Context, _ order.ID) (*order.Order, error) {
+		close(read)
+		<-release
+		loadErrs <- ctx.Err()
+		return o, nil
+	})
+
+	cachedRepo := New(repo, getStore(t), logger)
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := cachedRepo.Get(ctx, o.ID)
+		errs <- err
+	}()
+
+	// the caller starting the load gives up on it while the order is read
+	<-read
+	cancel()
+
+	if err := <-errs; !errors.Is(err, context.Canceled) {
+		t.Fatalf("could not match error: %s", err)
+	}
+
+	close(release)
+	if err := <-loadErrs; err != nil {
+		t.Fatalf("could not match load left running: %s", err)
+	}
+}
+
+func TestCache_Get_FailedWrite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	ctx := context.Background()
+	clock := order.NewFakeClock(time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC))
+	items := []order.LineItem{{SKU: "SKU-1", Quantity: 1, UnitPrice: order.NewMoney(1050, order.EUR)}}
+	o, err := order.Place(clock, order.NewID(), order.GenerateNumber(), order.UserID(order.NewID()), items)
+	if err != nil {
+		t.Fatalf("could not place order: %s", err)
+	}
+	o.PullEvents()
+
+	repo := order.NewMockRepo(ctrl)
+	logger := gologTest.NewNullLogger()
+
+	repo.EXPECT().Find(gomock.Any(), o.ID).Times(2).Return(o, nil)
+	repo.EXPECT().Add(ctx, gomock.Any()).Times(1).Return(order.ErrNotAdded)
+
+	cachedRepo := New(repo, getStore(t), logger)
+
+	// the use case changes the order it read, then fails to add it
+	found, err := cachedRepo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not find order: %s", err)
+	}
+
+	if err := found.MarkAsShipped(clock); err != nil {
+		t.Fatalf("could not mark order as shipped: %s", err)
+	}
+
+	e, err := cachedRepo.store.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not find order in the store: %s", err)
+	}
+
+	if e.Order.Status != order.Placed || !e.Order.ShippedAt.IsZero() {
+		t.Fatalf("could not match order in the store as it was read: %v", e.Order)
+	}
+
+	if err := cachedRepo.Add(ctx, found); !errors.Is(err, order.ErrNotAdded) {
+		t.Fatalf("could not match error: %s", err)
+	}
+
+	// the write outcome is uncertain, so the order is read again from the base
+	if _, err := cachedRepo.store.Get(ctx, o.ID); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("could find order in the store once not added: %s", err)
+	}
+
+	found, err = cachedRepo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not find order: %s", err)
+	}
+
+	if found.Status != order.Placed || !found.ShippedAt.IsZero() || len(found.Events()) != 0 {
+		t.Error("could not match order as it was stored")
+		t.Errorf("got: %v", found)
+		t.Errorf("want: %v", o)
+	}
+}
+
+func TestCache_Evict(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		o := &order.Order{ID: order.NewID()}
+
+		repo := order.NewMockRepo(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		repo.EXPECT().Find(gomock.Any(), o.ID).Times(2).Return(o, nil)
+
+		cachedRepo := New(repo, getStore(t), logger)
+
+		if _, err := cachedRepo.Get(ctx, o.ID); err != nil {
+			t.Fatalf("could not find order: %s", err)
+		}
+
+		if err := cachedRepo.Evict(ctx, o.ID); err != nil {
+			t.Fatalf("could not evict order: %s", err)
+		}
+
+		if _, err := cachedRepo.store.Get(ctx, o.ID); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("could find order in the store once evicted: %s", err)
+		}
+
+		if _, err := cachedRepo.Get(ctx, o.ID); err != nil {
+			t.Fatalf("could not find order: %s", err)
+		}
+	})
+
+	t.Run("while loaded", func(t *testing.T) {
+		tests := map[string]struct {
+			err error
+		}{
+			"found":     {},
+			"not found": {err: order.ErrNotFound},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				t.Cleanup(func() {
+					ctrl.Finish()
+				})
+
+				ctx := context.Background()
+				o := &order.Order{ID: order.NewID()}
+
+				repo := order.NewMockRepo(ctrl)
+				logger := gologTest.NewNullLogger()
+
+				read, release := make(chan struct{}), make(chan struct{})
+				repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).DoAndReturn(func(context.Context, order.ID) (*order.Order, error) {
+					close(read)
+					<-release
+					if test.err != nil {
+						return nil, test.err
+					}
+					return o, nil
+				})
+
+				cachedRepo := New(repo, getStore(t), logger)
+
+				errs := make(chan error, 1)
+				go func() {
+					_, err := cachedRepo.Get(ctx, o.ID)
+					errs <- err
+				}()
+
+				// the order is evicted once read, and before it is set in the cache
+				<-read
+				if err := cachedRepo.Evict(ctx, o.ID); err != nil {
+					t.Fatalf("could not evict order: %s", err)
+				}
+				close(release)
+
+				if err := <-errs; !errors.Is(err, test.err) {
+					t.Fatalf("could not match error: %s", err)
+				}
+
+				if _, err := cachedRepo.store.Get(ctx, o.ID); !errors.Is(err, cache.ErrNotFound) {
+					t.Fatalf("could find order in the store once evicted: %s", err)
+				}
+			})
+		}
+	})
+
+	t.Run("not added", func(t *testing.T) {
+		tests := map[string]struct {
+			err     error
+			evicted bool
+		}{
+			"ambiguous":               {err: order.ErrNotAdded, evicted: true},
+			"concurrent modification": {err: fmt.Errorf("%w: %w", order.ErrNotAdded, order.ErrConcurrentModification), evicted: true},
+			"duplicate number":        {err: fmt.Errorf("%w: %w", order.ErrNotAdded, order.ErrDuplicateNumber)},
+		}
+
+		for name, test := range tests {
+			t.Run(name, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				t.Cleanup(func() {
+					ctrl.Finish()
+				})
+
+				ctx := context.Background()
+				o := &order.Order{ID: order.NewID()}
+
+				repo := order.NewMockRepo(ctrl)
+				logger := gologTest.NewNullLogger()
+
+				repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).Return(o, nil)
+				repo.EXPECT().Add(ctx, o).Times(1).Return(test.err)
+
+				cachedRepo := New(repo, getStore(t), logger)
+
+				if _, err := cachedRepo.Get(ctx, o.ID); err != nil {
+					t.Fatalf("could not find order: %s", err)
+				}
+
+				if err := cachedRepo.Add(ctx, o); !errors.Is(err, test.err) {
+					t.Fatalf("could not match error: %s", err)
+				}
+
+				_, err := cachedRepo.store.Get(ctx, o.ID)
+				if evicted := errors.Is(err, cache.ErrNotFound); evicted != test.evicted {
+					t.Fatalf("could not match order evicted: %t", evicted)
+				}
+			})
+		}
+	})
+}
+
+func TestCache_Purge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	ctx := context.Background()
+	o := &order.Order{ID: order.NewID()}
+	missing := order.NewID()
+
+	repo := order.NewMockRepo(ctrl)
+	logger := gologTest.NewNullLogger()
+
+	repo.EXPECT().Find(gomock.Any(), o.ID).Times(2).Return(o, nil)
+	repo.EXPECT().Find(gomock.Any(), missing).Times(2).Return(nil, order.ErrNotFound)
+
+	cachedRepo := New(repo, getStore(t), logger)
+
+	// orders are read from the base once before the cache is purged, and once after
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			if _, err := cachedRepo.Get(ctx, o.ID); err != nil {
+				t.Fatalf("could not find order: %s", err)
+			}
+
+			if _, err := cachedRepo.Get(ctx, missing); !errors.Is(err, order.ErrNotFound) {
+				t.Fatalf("could not match error: %s", err)
+			}
+		}
+
+		if err := cachedRepo.Purge(ctx); err != nil {
+			t.Fatalf("could not purge cache: %s", err)
+		}
+	}
+}
+
+func TestCache_Get_Coalesced(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	ctx := context.Background()
+	o := &order.Order{ID: order.NewID()}
+
+	repo := order.NewMockRepo(ctrl)
+	logger := gologTest.NewNullLogger()
+
+	const concurrency = 8
+	release := make(chan struct{})
+	repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).DoAndReturn(func(context.Context, order.ID) (*order.Order, error) {
+		<-release
+		return o, nil
+	})
+
+	cachedRepo := New(repo, getStore(t), logger)
+	loads := newLoadsHelper(concurrency)
+	cachedRepo.loads = loads
+
+	loaded := counterValueHelper(t, loadsCounterVec.WithLabelValues(loadedResult))
+	deduplicated := counterValueHelper(t, loadsCounterVec.WithLabelValues(deduplicatedResult))
+
+	found := make([]*order.Order, concurrency)
+	errs := make([]error, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			found[i], errs[i] = cachedRepo.Get(ctx, o.ID)
+		}(i)
+	}
+
+	// the load is held until every caller waits on it, so that they all share it
+	for i := 0; i < concurrency; i++ {
+		<-loads.joined
+	}
+	close(release)
+	wg.Wait()
+
+	for i := range found {
+		if errs[i] != nil {
+			t.Fatalf("could not find order: %s", errs[i])
+		}
+
+		if !reflect.DeepEqual(found[i], o) {
+			t.Error("could not match orders")
+			t.Errorf("got: %v", found[i])
+			t.Errorf("want: %v", o)
+		}
+	}
+
+	if n := counterValueHelper(t, loadsCounterVec.WithLabelValues(loadedResult)) - loaded; n != 1 {
+		t.Errorf("could not match loads: %v", n)
+	}
+
+	if n := counterValueHelper(t, loadsCounterVec.WithLabelValues(deduplicatedResult)) - deduplicated; n != concurrency-1 {
+		t.Errorf("could not match deduplicated loads: %v", n)
+	}
+}
+
+func TestCache_Get_EarlyRefresh(t *testing.T) {
+	tests := map[string]struct {
+		opts        []Option
+		random      float64
+		transaction bool
+		refresh     bool
+		err         error
+	}{
+		"disabled": {
+			random: 0.5,
+		},
+		"not due": {
+			opts:   []Option{WithEarlyRefresh(1)},
+			random: 0,
+		},
+		"due": {
+			opts:    []Option{WithEarlyRefresh(1)},
+			random:  0.5,
+			refresh: true,
+		},
+		"due but failed": {
+			opts:    []Option{WithEarlyRefresh(1)},
+			random:  0.5,
+			refresh: true,
+			err:     errors.New("db down"),
+		},
+		"due within transaction": {
+			opts:        []Option{WithEarlyRefresh(1)},
+			random:      0.5,
+			transaction: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			ctx := context.Background()
+			if test.transaction {
+				ctx, _ = transaction.WithHooks(ctx)
+			}
+			cached := &order.Order{ID: order.NewID()}
+			refreshed := &order.Order{ID: cached.ID, Version: 1}
+
+			repo := order.NewMockRepo(ctrl)
+			logger := gologTest.NewNullLogger()
+
+			if test.refresh {
+				repo.EXPECT().Find(gomock.Any(), cached.ID).Times(1).Return(refreshed, test.err)
+			}
+
+			cachedRepo := New(repo, getStore(t), logger, test.opts...)
+			cachedRepo.random = func() float64 { return test.random }
+			loads := newLoadsHelper(1)
+			cachedRepo.loads = loads
+
+			// loaded in a minute and expiring in a second, the entry is due as soon as the random factor is not zero
+			e := &Entry{Order: cached, ExpiresAt: time.Now().Add(time.Second), Delta: time.Minute}
+			if err := cachedRepo.store.Set(ctx, cached.ID, e, time.Minute); err != nil {
+				t.Fatalf("could not set order in the store: %s", err)
+			}
+
+			// the cached order is handed out right away, whether it is refreshed or not
+			found, err := cachedRepo.Get(ctx, cached.ID)
+			if err != nil {
+				t.Fatalf("could not find order: %s", err)
+			}
+
+			if !reflect.DeepEqual(found, cached) {
+				t.Error("could not match orders")
+				t.Errorf("got: %v", found)
+				t.Errorf("want: %v", cached)
+			}
+
+			if !test.refresh {
+				return
+			}
+
+			<-loads.done
+			want := refreshed
+			if test.err != nil {
+				want = cached
+			}
+
+			e, err = cachedRepo.store.Get(ctx, cached.ID)
+			if err != nil {
+				t.Fatalf("could not find order in the store: %s", err)
+			}
+
+			if !reflect.DeepEqual(e.Order, want) {
+				t.Error("could not match orders in the store")
+				t.Errorf("got: %v", e.Order)
+				t.Errorf("want: %v", want)
+			}
+		})
+	}
+}
+
+func TestCache_Get_TTL(t *testing.T) {
+	tests := map[string]struct {
+		opts   []Option
+		random float64
+		want   time.Duration
+	}{
+		"default": {
+			random: 0.5,
+			want:   DefaultTTL,
+		},
+		"no jitter": {
+			opts:   []Option{WithTTL(time.Hour, 0)},
+			random: 0.5,
+			want:   time.Hour,
+		},
+		"jitter": {
+			opts:   []Option{WithTTL(time.Hour, 10*time.Minute)},
+			random: 0.5,
+			want:   time.Hour + 5*time.Minute,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			ctx := context.Background()
+			o := &order.Order{ID: order.NewID()}
+
+			repo := order.NewMockRepo(ctrl)
+			logger := gologTest.NewNullLogger()
+
+			repo.EXPECT().Find(gomock.Any(), o.ID).Times(1).Return(o, nil)
+
+			cachedRepo := New(repo, getStore(t), logger, test.opts...)
+			cachedRepo.random = func() float64 { return test.random }
+
+			start := time.Now()
+			if _, err := cachedRepo.Get(ctx, o.ID); err != nil {
+				t.Fatalf("could not find order: %s", err)
+			}
+			end := time.Now()
+
+			found, err := cachedRepo.store.Get(ctx, o.ID)
+			if err != nil {
+				t.Fatalf("could not find order in the store: %s", err)
+			}
+
+			if found.ExpiresAt.Before(start.Add(test.want)) || found.ExpiresAt.After(end.Add(test.want)) {
+				t.Error("could not match expiration")
+				t.Errorf("got: %v", found.ExpiresAt.Sub(start))
+				t.Errorf("want: %v", test.want)
+			}
+		})
+	}
+}
+
+func TestCache_Get_NegativeTTLDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(func() {
+		ctrl.Finish()
+	})
+
+	ctx := context.Background()
+	id := order.NewID()
+
+	repo := order.NewMockRepo(ctrl)
+	logger := gologTest.NewNullLogger()
+
+	repo.EXPECT().Find(gomock.Any(), id).Times(2).Return(nil, order.ErrNotFound)
+
+	cachedRepo := New(repo, getStore(t), logger, WithNegativeTTL(0))
+
+	for i := 0; i < 2; i++ {
+		if _, err := cachedRepo.Get(ctx, id); !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	}
+
+	if _, err := cachedRepo.store.Get(ctx, id); !errors.Is(err, cache.ErrNotFound) {
+		t.Fatalf("could find order in the store: %s", err)
+	}
+}
+
+// counterValueHelper returns the current value of the counter
+func counterValueHelper(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+
+	var m dto.Metric
+	if err := c.Write(&m); err != nil {
+		t.Fatalf("could not read counter: %s", err)
+	}
+
+	return m.GetCounter().GetValue()
+}
+
+// getStore returns a store of the default config, closed once the test is done
+func getStore(t *testing.T) cache.Cache[order.ID, *Entry] {
+	t.Helper()
+
+	store := NewStore(DefaultConfig())
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	return store
+}
+
+// loadsHelper coalesces the loads as the cache does, reporting every call once it joined a load, and once it is done
+type loadsHelper struct {
+	group  singleflight.Group
+	joined chan struct{}
+	done   chan struct{}
+}
+
+// newLoadsHelper returns a loadsHelper reporting up to n calls without being waited on
+func newLoadsHelper(n int) *loadsHelper {
+	return &loadsHelper{joined: make(chan struct{}, n), done: make(chan struct{}, n)}
+}
+
+// DoChan calls the singleflight.Group, reporting the call once it joined the load,
+// and once the load is done, the order being set in the store by then
+func (l *loadsHelper) DoChan(key string, fn func() (any, error)) <-chan singleflight.Result {
+	ch, res := l.group.DoChan(key, fn), make(chan singleflight.Result, 1)
+	l.joined <- struct{}{}
+	go func() {
+		r := <-ch
+		l.done <- struct{}{}
+		res <- r
+	}()
+
+	return res
 }
@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"github.com/damianopetrungaro/go-cache"
+	"github.com/organization/order-service"
+	"time"
+)
+
+// ErrConfigNotValid is returned when a Config cannot be used to cache orders
+var ErrConfigNotValid = errors.New("cache config not valid")
+
+// Default values of the Config
+const (
+	DefaultTTL             = time.Minute
+	DefaultJitter          = 6 * time.Second
+	DefaultNegativeTTL     = 5 * time.Second
+	DefaultCapacity        = 10_000
+	DefaultCleanupInterval = time.Minute
+	DefaultLoadTimeout     = 5 * time.Second
+)
+
+// Config represents the configuration of the cache layer
+type Config struct {
+	// Enabled tells whether the orders are cached at all
+	Enabled bool
+	// TTL is the time an order is cached for
+	TTL time.Duration
+	// Jitter is the maximum random time added to the TTL of every order, so that orders cached together do not expire together
+	Jitter time.Duration
+	// NegativeTTL is the time an order not found is remembered for, orders not found are not remembered when zero
+	NegativeTTL time.Duration
+	// Capacity is the number of entries past which the in-memory store cleans up the expired ones as new ones are set
+	Capacity int
+	// CleanupInterval is the interval the in-memory store cleans up the expired entries at
+	CleanupInterval time.Duration
+	// Dir is the directory the orders are cached in, shared by the processes caching in it (see NewFileStore)
+	// Orders are cached in the memory of every process when empty
+	Dir string
+	// EarlyRefresh scales the probabilistic early refresh of the entries, disabled when zero (see WithEarlyRefresh)
+	EarlyRefresh float64
+}
+
+// DefaultConfig returns the Config of an enabled cache without early refresh
+func DefaultConfig() Config {
+	return Config{
+		Enabled:         true,
+		TTL:             DefaultTTL,
+		Jitter:          DefaultJitter,
+		NegativeTTL:     DefaultNegativeTTL,
+		Capacity:        DefaultCapacity,
+		CleanupInterval: DefaultCleanupInterval,
+	}
+}
+
+// Validate returns ErrConfigNotValid when the config cannot be used to cache orders
+// A disabled cache is always valid
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	switch {
+	case c.TTL <= 0:
+		return fmt.Errorf("%w: ttl %s is not positive", ErrConfigNotValid, c.TTL)
+	case c.Jitter < 0:
+		return fmt.Errorf("%w: jitter %s is negative", ErrConfigNotValid, c.Jitter)
+	case c.NegativeTTL < 0:
+		return fmt.Errorf("%w: negative ttl %s is negative", ErrConfigNotValid, c.NegativeTTL)
+	case c.Capacity <= 0:
+		return fmt.Errorf("%w: capacity %d is not positive", ErrConfigNotValid, c.Capacity)
+	case c.CleanupInterval <= 0:
+		return fmt.Errorf("%w: cleanup interval %s is not positive", ErrConfigNotValid, c.CleanupInterval)
+	case c.EarlyRefresh < 0:
+		return fmt.Errorf("%w: early refresh %g is negative", ErrConfigNotValid, c.EarlyRefresh)
+	}
+
+	return nil
+}
+
+// Options returns the options of a Cache configured by c
+func (c Config) Options() []Option {
+	return []Option{
+		WithTTL(c.TTL, c.Jitter),
+		WithNegativeTTL(c.NegativeTTL),
+		WithEarlyRefresh(c.EarlyRefresh),
+	}
+}
+
+// Shared reports whether the orders are cached in a store shared by the processes, so that they can be evicted by any of them
+func (c Config) Shared() bool {
+	return c.Dir != ""
+}
+
+// NewStore returns an in-memory store with the capacity and cleanup interval of the config
+// It cleans up the expired entries in the background until it is closed
+func NewStore(c Config) *cache.InMem[order.ID, *Entry] {
+	return cache.NewInMemory[order.ID, *Entry](c.CleanupInterval, c.Capacity)
+}
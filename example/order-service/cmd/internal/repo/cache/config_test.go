@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		config func(*Config)
+		err    error
+	}{
+		"default": {
+			config: func(*Config) {},
+		},
+		"disabled": {
+			config: func(c *Config) {
+				c.Enabled = false
+				c.TTL = 0
+			},
+		},
+		"negative caching disabled": {
+			config: func(c *Config) {
+				c.NegativeTTL = 0
+			},
+		},
+		"ttl not positive": {
+			config: func(c *Config) {
+				c.TTL = 0
+			},
+			err: ErrConfigNotValid,
+		},
+		"jitter negative": {
+			config: func(c *Config) {
+				c.Jitter = -time.Second
+			},
+			err: ErrConfigNotValid,
+		},
+		"negative ttl negative": {
+			config: func(c *Config) {
+				c.NegativeTTL = -time.Second
+			},
+			err: ErrConfigNotValid,
+		},
+		"capacity not positive": {
+			config: func(c *Config) {
+				c.Capacity = 0
+			},
+			err: ErrConfigNotValid,
+		},
+		"cleanup interval not positive": {
+			config: func(c *Config) {
+				c.CleanupInterval = 0
+			},
+			err: ErrConfigNotValid,
+		},
+		"early refresh negative": {
+			config: func(c *Config) {
+				c.EarlyRefresh = -1
+			},
+			err: ErrConfigNotValid,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := DefaultConfig()
+			test.config(&c)
+
+			if err := c.Validate(); !errors.Is(err, test.err) {
+				t.Error("could not match error")
+				t.Errorf("got: %v", err)
+				t.Errorf("want: %v", test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/damianopetrungaro/go-cache"
+	"github.com/organization/order-service"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var _ Store = &FileStore{}
+
+// fileExt is the extension of the files the entries are stored in
+const fileExt = ".json"
+
+// fileEntry represents an entry as stored in its file
+type fileEntry struct {
+	Entry *Entry `json:"entry"`
+	// ExpiresAt is the time the store expires the entry at, zero when it never expires
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// FileStore represents a store of the entries in a directory, one JSON file per order
+// The processes caching orders in the same directory share them, so that an order evicted by any of them,
+// such as the CLI during an incident, is evicted for all of them
+// Expired entries are ignored and left in the directory until they are set again or purged
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a store of the entries in the directory, creating it when missing
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("could not create cache directory: %w", err)
+	}
+
+	return &FileStore{dir: dir}, nil
+}
+
+// Get returns the entry of the order, cache.ErrNotFound when not stored and cache.ErrExpired once expired
+func (s *FileStore) Get(ctx context.Context, id order.ID) (*Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", cache.ErrNotGet, err)
+	}
+
+	b, err := os.ReadFile(s.path(id))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, cache.ErrNotFound
+	case err != nil:
+		return nil, fmt.Errorf("%w: %w", cache.ErrNotGet, err)
+	}
+
+	var fe fileEntry
+	if err := json.Unmarshal(b, &fe); err != nil {
+		return nil, fmt.Errorf("%w: %w", cache.ErrNotGet, err)
+	}
+
+	if !fe.ExpiresAt.IsZero() && !time.Now().Before(fe.ExpiresAt) {
+		return nil, cache.ErrExpired
+	}
+
+	return fe.Entry, nil
+}
+
+// Set stores the entry of the order for the given ttl, cache.NoExpiration storing it until deleted
+// The file is replaced at once, so that the other processes never read a partially written entry
+func (s *FileStore) Set(ctx context.Context, id order.ID, e *Entry, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", cache.ErrNotSet, err)
+	}
+
+	fe := fileEntry{Entry: e}
+	if ttl != cache.NoExpiration {
+		fe.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	b, err := json.Marshal(fe)
+	if err != nil {
+		return fmt.Errorf("%w: %w", cache.ErrNotSet, err)
+	}
+
+	f, err := os.CreateTemp(s.dir, id.String()+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("%w: %w", cache.ErrNotSet, err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("%w: %w", cache.ErrNotSet, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("%w: %w", cache.ErrNotSet, err)
+	}
+
+	if err := os.Rename(f.Name(), s.path(id)); err != nil {
+		return fmt.Errorf("%w: %w", cache.ErrNotSet, err)
+	}
+
+	return nil
+}
+
+// Delete removes the entry of the order, an order not stored being deleted already
+func (s *FileStore) Delete(ctx context.Context, id order.ID) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", cache.ErrNotDelete, err)
+	}
+
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %w", cache.ErrNotDelete, err)
+	}
+
+	return nil
+}
+
+// Purge removes the entries of every order, expired ones included
+func (s *FileStore) Purge(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", cache.ErrNotDelete, err)
+	}
+
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("%w: %w", cache.ErrNotDelete, err)
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), fileExt) {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(s.dir, f.Name())); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %w", cache.ErrNotDelete, err)
+		}
+	}
+
+	return nil
+}
+
+// path returns the path of the file the entry of the order is stored in
+func (s *FileStore) path(id order.ID) string {
+	return filepath.Join(s.dir, id.String()+fileExt)
+}
@@ -0,0 +1,190 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"github.com/damianopetrungaro/go-cache"
+	gologTest "github.com/damianopetrungaro/golog/test"
+	"github.com/golang/mock/gomock"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/memory"
+	"github.com/organization/order-service/cmd/internal/repo/repotest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFileStore_Contract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) order.Repo {
+		logger := gologTest.NewNullLogger()
+		return New(memory.New(logger), getFileStore(t, t.TempDir()), logger)
+	})
+}
+
+func TestFileStore(t *testing.T) {
+	t.Run("set", func(t *testing.T) {
+		ctx := context.Background()
+		store := getFileStore(t, t.TempDir())
+		e := &Entry{Order: placeOrderHelper(t), ExpiresAt: time.Now().Add(time.Minute).UTC(), Delta: time.Millisecond}
+
+		if err := store.Set(ctx, e.Order.ID, e, time.Minute); err != nil {
+			t.Fatalf("could not set entry: %s", err)
+		}
+
+		found, err := store.Get(ctx, e.Order.ID)
+		if err != nil {
+			t.Fatalf("could not find entry: %s", err)
+		}
+
+		if !reflect.DeepEqual(found, e) {
+			t.Error("could not match entries")
+			t.Errorf("got: %v", found)
+			t.Errorf("want: %v", e)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		store := getFileStore(t, t.TempDir())
+
+		if _, err := store.Get(context.Background(), order.NewID()); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		ctx := context.Background()
+		store := getFileStore(t, t.TempDir())
+		id := order.NewID()
+
+		if err := store.Set(ctx, id, &Entry{}, time.Nanosecond); err != nil {
+			t.Fatalf("could not set entry: %s", err)
+		}
+		time.Sleep(time.Millisecond)
+
+		if _, err := store.Get(ctx, id); !errors.Is(err, cache.ErrExpired) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx := context.Background()
+		store := getFileStore(t, t.TempDir())
+		id := order.NewID()
+
+		if err := store.Set(ctx, id, &Entry{}, cache.NoExpiration); err != nil {
+			t.Fatalf("could not set entry: %s", err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if err := store.Delete(ctx, id); err != nil {
+				t.Fatalf("could not delete entry: %s", err)
+			}
+		}
+
+		if _, err := store.Get(ctx, id); !errors.Is(err, cache.ErrNotFound) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		ctx := context.Background()
+		store := getFileStore(t, t.TempDir())
+		ids := []order.ID{order.NewID(), order.NewID()}
+
+		for _, id := range ids {
+			if err := store.Set(ctx, id, &Entry{}, cache.NoExpiration); err != nil {
+				t.Fatalf("could not set entry: %s", err)
+			}
+		}
+
+		if err := store.Purge(ctx); err != nil {
+			t.Fatalf("could not purge entries: %s", err)
+		}
+
+		for _, id := range ids {
+			if _, err := store.Get(ctx, id); !errors.Is(err, cache.ErrNotFound) {
+				t.Fatalf("could not match error: %s", err)
+			}
+		}
+	})
+}
+
+func TestFileStore_Shared(t *testing.T) {
+	tests := map[string]func(ctx context.Context, c *Cache, id order.ID) error{
+		"evict": func(ctx context.Context, c *Cache, id order.ID) error {
+			return c.Evict(ctx, id)
+		},
+		"purge": func(ctx context.Context, c *Cache, _ order.ID) error {
+			return c.Purge(ctx)
+		},
+	}
+
+	for name, evict := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			t.Cleanup(func() {
+				ctrl.Finish()
+			})
+
+			ctx := context.Background()
+			o := placeOrderHelper(t)
+			dir := t.TempDir()
+
+			repo := order.NewMockRepo(ctrl)
+			logger := gologTest.NewNullLogger()
+
+			repo.EXPECT().Find(gomock.Any(), o.ID).Times(2).Return(o, nil)
+
+			// the service and the CLI cache orders in the same directory from two processes
+			service := New(repo, getFileStore(t, dir), logger)
+			cli := New(repo, getFileStore(t, dir), logger)
+
+			if _, err := service.Get(ctx, o.ID); err != nil {
+				t.Fatalf("could not find order: %s", err)
+			}
+
+			if err := evict(ctx, cli, o.ID); err != nil {
+				t.Fatalf("could not evict order: %s", err)
+			}
+
+			// the order is read from the base again by the service once evicted by the CLI
+			found, err := service.Get(ctx, o.ID)
+			if err != nil {
+				t.Fatalf("could not find order: %s", err)
+			}
+
+			if !reflect.DeepEqual(found, o) {
+				t.Error("could not match orders")
+				t.Errorf("got: %v", found)
+				t.Errorf("want: %v", o)
+			}
+		})
+	}
+}
+
+// getFileStore returns a store of the entries in the directory
+func getFileStore(t *testing.T, dir string) *FileStore {
+	t.Helper()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("could not create file store: %s", err)
+	}
+
+	return store
+}
+
+// placeOrderHelper returns an order placed, as stored in the base
+func placeOrderHelper(t *testing.T) *order.Order {
+	t.Helper()
+
+	clock := order.NewFakeClock(time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC))
+	items := []order.LineItem{{SKU: "SKU-1", Quantity: 1, UnitPrice: order.NewMoney(1050, order.EUR)}}
+	o, err := order.Place(clock, order.NewID(), order.GenerateNumber(), order.UserID(order.NewID()), items)
+	if err != nil {
+		t.Fatalf("could not place order: %s", err)
+	}
+	o.PullEvents()
+
+	return o
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Results of the lookups of the orders in the cache, a negative hit being an order remembered as not found
+const (
+	hitResult         = "hit"
+	negativeHitResult = "negative_hit"
+	missResult        = "miss"
+)
+
+// Results of the loads of the orders missed by the cache
+const (
+	loadedResult       = "loaded"
+	deduplicatedResult = "deduplicated"
+)
+
+// Reasons of the evictions of the orders from the cache
+const (
+	evictReason        = "evict"
+	purgeReason        = "purge"
+	writeFailureReason = "write_failure"
+)
+
+var (
+	// lookupsCounterVec counts the lookups of the orders in the cache
+	lookupsCounterVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_lookups_total",
+			Help: "cache lookups by result, a negative hit being an order remembered as not found",
+		},
+		[]string{"result"})
+
+	// loadsCounterVec counts the loads from the base, deduplicated ones sharing the load of a concurrent Get
+	loadsCounterVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_loads_total",
+			Help: "cache loads from the base by result, deduplicated loads sharing a concurrent one",
+		},
+		[]string{"result"})
+
+	// earlyRefreshesCounter counts the entries loaded again before they expired
+	earlyRefreshesCounter = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "cache_early_refreshes_total",
+			Help: "cache entries refreshed before they expired",
+		})
+
+	// evictionsCounterVec counts the orders evicted from the cache by reason, a purge counting once
+	evictionsCounterVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_evictions_total",
+			Help: "cache evictions by reason, a purge counting once",
+		},
+		[]string{"reason"})
+)
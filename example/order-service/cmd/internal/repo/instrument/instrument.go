@@ -6,3 +6,8 @@ import "github.com/organization/order-service"
 func New(base order.Repo, name string) order.Repo {
 	return NewRepoWithPrometheus(NewRepoWithTracing(base, name), name)
 }
+
+// NewLocker returns an instrumented order.Locker
+func NewLocker(base order.Locker, name string) order.Locker {
+	return NewLockerWithPrometheus(NewLockerWithTracing(base, name), name)
+}
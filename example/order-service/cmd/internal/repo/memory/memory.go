@@ -0,0 +1,173 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/organization/order-service"
+)
+
+var (
+	_ order.Repo   = &Memory{}
+	_ order.Lister = &Memory{}
+)
+
+// Memory represents an in-memory storage of the orders, meant for tests and local development
+// It is safe for concurrent use and stores copies of the orders, so that callers cannot change them without Add
+type Memory struct {
+	mu      sync.RWMutex
+	orders  map[order.ID]*order.Order
+	numbers map[order.Number]order.ID
+	logger  golog.Logger
+}
+
+// New returns an empty in-memory layer implementing order.Repo and order.Lister
+func New(logger golog.Logger) *Memory {
+	return &Memory{
+		orders:  map[order.ID]*order.Order{},
+		numbers: map[order.Number]order.ID{},
+		logger:  logger,
+	}
+}
+
+// NewFromFile returns an in-memory layer seeded with the orders of the JSON fixture file at path (see Seed)
+func NewFromFile(ctx context.Context, path string, logger golog.Logger) (*Memory, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open fixture: %w", err)
+	}
+	defer f.Close()
+
+	m := New(logger)
+	if err := m.Seed(ctx, f); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// Seed adds the orders read from r, encoded as a JSON array of orders
+// The versions of the orders are ignored, every order is added as if it was never stored
+func (m *Memory) Seed(ctx context.Context, r io.Reader) error {
+	var orders []*order.Order
+	if err := json.NewDecoder(r).Decode(&orders); err != nil {
+		return fmt.Errorf("could not decode fixture: %w", err)
+	}
+
+	for _, o := range orders {
+		o.Version = 0
+		if err := m.Add(ctx, o); err != nil {
+			return fmt.Errorf("could not seed order %s: %w", o.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Get returns a copy of the stored order
+func (m *Memory) Get(ctx context.Context, id order.ID) (*order.Order, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	o, ok := m.orders[id]
+	if !ok {
+		m.logger.Debug(ctx, "order was not found in memory")
+		return nil, order.ErrNotFound
+	}
+
+	return o.Clone(), nil
+}
+
+// Add stores a copy of the order, if its version matches the stored one
+// It increments the version of the order once stored
+func (m *Memory) Add(ctx context.Context, o *order.Order) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	version := 0
+	if stored, ok := m.orders[o.ID]; ok {
+		version = stored.Version
+	}
+
+	if o.Version != version {
+		m.logger.Warn(ctx, "order was modified concurrently in memory")
+		return fmt.Errorf("%w: %w", order.ErrNotAdded, order.ErrConcurrentModification)
+	}
+
+	if id, ok := m.numbers[o.Number]; ok && id != o.ID {
+		m.logger.Warn(ctx, "order number was already taken in memory")
+		return fmt.Errorf("%w: %w", order.ErrNotAdded, order.ErrDuplicateNumber)
+	}
+
+	stored := o.Clone()
+	stored.Version++
+	m.orders[o.ID] = stored
+	m.numbers[o.Number] = o.ID
+	o.Version = stored.Version
+
+	return nil
+}
+
+// List returns a page of copies of the orders matching the filter, from the most recently placed
+func (m *Memory) List(ctx context.Context, f order.Filter, c order.Cursor) (order.Page, error) {
+	if err := f.Validate(); err != nil {
+		return order.Page{}, fmt.Errorf("%w: %w", order.ErrNotListed, err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var orders []*order.Order
+	for _, o := range m.orders {
+		if f.Matches(o) && follows(o, c) {
+			orders = append(orders, o)
+		}
+	}
+
+	sort.Slice(orders, func(i, j int) bool {
+		if c.Direction == order.Backward {
+			return before(orders[i], orders[j])
+		}
+		return before(orders[j], orders[i])
+	})
+
+	if len(orders) > f.Size()+1 {
+		orders = orders[:f.Size()+1]
+	}
+
+	for i, o := range orders {
+		orders[i] = o.Clone()
+	}
+
+	return order.NewPage(orders, f.Size(), c), nil
+}
+
+// follows reports whether the order follows the cursor in its direction
+func follows(o *order.Order, c order.Cursor) bool {
+	if c.IsZero() {
+		return true
+	}
+
+	at := &order.Order{PlacedAt: c.PlacedAt, ID: c.ID}
+	if c.Direction == order.Backward {
+		return before(at, o)
+	}
+
+	return before(o, at)
+}
+
+// before reports whether a sorts before b by placed at time, ties being broken by ID as the database does
+func before(a, b *order.Order) bool {
+	if !a.PlacedAt.Equal(b.PlacedAt) {
+		return a.PlacedAt.Before(b.PlacedAt)
+	}
+
+	return bytes.Compare(a.ID[:], b.ID[:]) < 0
+}
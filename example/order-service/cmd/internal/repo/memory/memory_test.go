@@ -0,0 +1,194 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	gologTest "github.com/damianopetrungaro/golog/test"
+	"github.com/google/uuid"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/repotest"
+)
+
+func TestMemory_Contract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) order.Repo {
+		return New(gologTest.NewNullLogger())
+	})
+}
+
+func TestMemory_List(t *testing.T) {
+	ctx := context.Background()
+	repo := New(gologTest.NewNullLogger())
+	clock := order.NewFakeClock(time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC))
+	user := order.UserID(uuid.New())
+
+	var placed []*order.Order
+	for i := 0; i < 5; i++ {
+		o := addPlacedOrderHelper(t, repo, clock, user)
+		placed = append([]*order.Order{o}, placed...)
+		clock.Advance(time.Hour)
+	}
+	addPlacedOrderHelper(t, repo, clock, order.UserID(uuid.New()))
+
+	t.Run("filter", func(t *testing.T) {
+		p, err := repo.List(ctx, order.Filter{PlacedBy: user}, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		matchesIDsHelper(t, placed, p.Orders)
+		if !p.Next.IsZero() || !p.Previous.IsZero() {
+			t.Fatalf("could not match a single page: %v", p)
+		}
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		f := order.Filter{PlacedBy: user, Limit: 2}
+
+		first, err := repo.List(ctx, f, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+		matchesIDsHelper(t, placed[:2], first.Orders)
+
+		second, err := repo.List(ctx, f, first.Next)
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+		matchesIDsHelper(t, placed[2:4], second.Orders)
+
+		last, err := repo.List(ctx, f, second.Next)
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+		matchesIDsHelper(t, placed[4:], last.Orders)
+		if !last.Next.IsZero() {
+			t.Fatalf("could not match the last page: %v", last.Next)
+		}
+
+		back, err := repo.List(ctx, f, second.Previous)
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+		matchesIDsHelper(t, placed[:2], back.Orders)
+		if !back.Previous.IsZero() {
+			t.Fatalf("could not match the first page: %v", back.Previous)
+		}
+	})
+
+	t.Run("copies", func(t *testing.T) {
+		p, err := repo.List(ctx, order.Filter{PlacedBy: user, Limit: 1}, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		p.Orders[0].Items[0].Quantity++
+		found, err := repo.Get(ctx, p.Orders[0].ID)
+		if err != nil {
+			t.Fatalf("could not get order: %s", err)
+		}
+
+		if found.Items[0].Quantity == p.Orders[0].Items[0].Quantity {
+			t.Fatalf("could not match stored order left untouched: %v", found.Items)
+		}
+	})
+
+	t.Run("filter not valid", func(t *testing.T) {
+		if _, err := repo.List(ctx, order.Filter{Limit: -1}, order.Cursor{}); !errors.Is(err, order.ErrNotListed) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+}
+
+func TestNewFromFile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		repo, err := NewFromFile(ctx, "../../../../config/fixtures/orders.json", gologTest.NewNullLogger())
+		if err != nil {
+			t.Fatalf("could not seed orders: %s", err)
+		}
+
+		p, err := repo.List(ctx, order.Filter{}, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		if len(p.Orders) != 3 {
+			t.Fatalf("could not match number of seeded orders: %d", len(p.Orders))
+		}
+
+		for _, o := range p.Orders {
+			if o.Version != 1 {
+				t.Fatalf("could not match version of seeded order: %d", o.Version)
+			}
+		}
+
+		delivered := p.Orders[2]
+		if delivered.Status != order.Delivered || delivered.ShippingAddress.IsZero() || delivered.DeliveredAt.IsZero() {
+			t.Fatalf("could not match seeded order: %v", delivered)
+		}
+	})
+
+	t.Run("file not found", func(t *testing.T) {
+		if _, err := NewFromFile(ctx, "not-found.json", gologTest.NewNullLogger()); err == nil {
+			t.Fatal("could not match error")
+		}
+	})
+}
+
+func TestMemory_Seed(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not decoded", func(t *testing.T) {
+		repo := New(gologTest.NewNullLogger())
+		if err := repo.Seed(ctx, strings.NewReader(`{"ID": "not an array"}`)); err == nil {
+			t.Fatal("could not match error")
+		}
+	})
+
+	t.Run("duplicate number", func(t *testing.T) {
+		const fixture = `[
+			{"ID": "0186f3e0-1a40-7b2c-8d4e-1f2a3b4c5d01", "Number": "ORD-230318-000001-Z", "Status": "placed"},
+			{"ID": "0186f90a-6e00-7c3d-9e5f-2a3b4c5d6e02", "Number": "ORD-230318-000001-Z", "Status": "placed"}
+		]`
+
+		repo := New(gologTest.NewNullLogger())
+		if err := repo.Seed(ctx, strings.NewReader(fixture)); !errors.Is(err, order.ErrDuplicateNumber) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+}
+
+func addPlacedOrderHelper(t *testing.T, repo *Memory, clock order.Clock, user order.UserID) *order.Order {
+	t.Helper()
+
+	items := []order.LineItem{{SKU: order.SKU("SKU-" + uuid.NewString()[:8]), Quantity: 1, UnitPrice: order.NewMoney(1050, order.EUR)}}
+	o, err := order.Place(clock, order.NewID(), order.GenerateNumber(), user, items)
+	if err != nil {
+		t.Fatalf("could not place order: %s", err)
+	}
+
+	if err := repo.Add(context.Background(), o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	return o
+}
+
+func matchesIDsHelper(t *testing.T, want, got []*order.Order) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("could not match number of orders: got %d, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		if got[i].ID != want[i].ID {
+			t.Fatalf("could not match order %d: got %s, want %s", i, got[i].ID, want[i].ID)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/organization/order-service"
+)
+
+// marshalEvent encodes a domain event to be stored in the database
+func marshalEvent(e order.Event) ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// unmarshalEvent decodes a domain event stored in the database using its name
+func unmarshalEvent(name string, payload []byte) (order.Event, error) {
+	switch name {
+	case order.OrderPlaced{}.EventName():
+		return decodeEvent[order.OrderPlaced](name, payload)
+	case order.OrderShipped{}.EventName():
+		return decodeEvent[order.OrderShipped](name, payload)
+	case order.OrderDelivered{}.EventName():
+		return decodeEvent[order.OrderDelivered](name, payload)
+	case order.OrderCancelled{}.EventName():
+		return decodeEvent[order.OrderCancelled](name, payload)
+	case order.ShippingAddressChanged{}.EventName():
+		return decodeEvent[order.ShippingAddressChanged](name, payload)
+	default:
+		return nil, fmt.Errorf("unknown event: %s", name)
+	}
+}
+
+func decodeEvent[E order.Event](name string, payload []byte) (order.Event, error) {
+	var e E
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, fmt.Errorf("could not decode %s event: %w", name, err)
+	}
+
+	return e, nil
+}
@@ -0,0 +1,351 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/transaction"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+var (
+	_ order.Repo   = &EventStore{}
+	_ order.Locker = &EventStore{}
+)
+
+// Unique constraints of the order_streams table
+const (
+	orderStreamsPrimaryKey = "order_streams_pkey"
+	orderStreamsNumberKey  = "order_streams_number_key"
+)
+
+const (
+	insertStreamQuery = `INSERT INTO order_streams (order_id, number, version) VALUES ($1, $2, $3)`
+
+	updateStreamQuery = `UPDATE order_streams SET version = $3 WHERE order_id = $1 AND version = $2`
+
+	selectStreamQuery = `SELECT version FROM order_streams WHERE order_id = $1`
+
+	selectStreamExistsQuery = `SELECT EXISTS (SELECT 1 FROM order_streams WHERE order_id = $1)`
+
+	insertEventQuery = `INSERT INTO order_events (order_id, version, name, payload, occurred_at) VALUES ($1, $2, $3, $4, $5)`
+
+	selectEventsQuery = `SELECT version, name, payload FROM order_events WHERE order_id = $1 AND version > $2 ORDER BY version`
+
+	selectSnapshotQuery = `SELECT version, payload FROM order_snapshots WHERE order_id = $1`
+
+	upsertSnapshotQuery = `INSERT INTO order_snapshots (order_id, version, payload) VALUES ($1, $2, $3)
+ON CONFLICT (order_id) DO UPDATE SET version = EXCLUDED.version, payload = EXCLUDED.payload, taken_at = now() AT TIME ZONE 'utc'`
+)
+
+// DefaultSnapshotEvery is the number of events after which the EventStore snapshots an order by default
+const DefaultSnapshotEvery = 50
+
+// EventStore represents a database layer for the order.Repo storing every order as an append-only stream of its events
+// The full history of the orders is kept, the latest state being rebuilt by replaying the events
+// from the latest snapshot, taken every few events to keep reads fast on long streams
+// The Version of an order is the number of events of its stream
+type EventStore struct {
+	db            *sql.DB
+	snapshotEvery int
+	outbox        bool
+	logger        golog.Logger
+}
+
+// NewEventStore returns an event sourced database integration layer implementing order.Repo
+// An order is snapshotted every snapshotEvery events, snapshots are disabled when it is not positive
+func NewEventStore(db *sql.DB, snapshotEvery int, logger golog.Logger) *EventStore {
+	return &EventStore{
+		db:            db,
+		snapshotEvery: snapshotEvery,
+		logger:        logger,
+	}
+}
+
+// NewEventStoreWithOutbox returns an event sourced database integration layer implementing order.Repo
+// which stores the appended events in the outbox table as well, within the same transaction
+func NewEventStoreWithOutbox(db *sql.DB, snapshotEvery int, logger golog.Logger) *EventStore {
+	return &EventStore{
+		db:            db,
+		snapshotEvery: snapshotEvery,
+		outbox:        true,
+		logger:        logger,
+	}
+}
+
+// Get rebuilds an order from its latest snapshot and the events appended since then
+// It runs within the transaction carried by the context if any
+func (s *EventStore) Get(ctx context.Context, id order.ID) (*order.Order, error) {
+	exec := executor(ctx, s.db)
+
+	var (
+		snapshot *order.Order
+		version  int
+		payload  []byte
+	)
+	err := exec.QueryRowContext(ctx, selectSnapshotQuery, id.String()).Scan(&version, &payload)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		s.logger.With(golog.Err(err)).Error(ctx, "order snapshot was not read from the database")
+		return nil, order.ErrNotFound
+	default:
+		snapshot = &order.Order{}
+		if err := json.Unmarshal(payload, snapshot); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order snapshot was not decoded")
+			return nil, order.ErrNotFound
+		}
+	}
+
+	events, last, err := getEvents(ctx, exec, id, version)
+	if err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "order events were not read from the database")
+		return nil, order.ErrNotFound
+	}
+
+	if snapshot == nil && len(events) == 0 {
+		return nil, order.ErrNotFound
+	}
+
+	o, err := order.Replay(snapshot, events...)
+	if err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "order events were not replayed")
+		return nil, fmt.Errorf("%w: %w", order.ErrNotFound, err)
+	}
+
+	o.Version = version
+	if len(events) > 0 {
+		o.Version = last
+	}
+
+	return o, nil
+}
+
+// GetForUpdate locks the stream of an order until the end of the transaction carried by the context,
+// then rebuilds the order as Get does
+// It returns ErrLocked when the mode does not wait for the lock held by another transaction
+func (s *EventStore) GetForUpdate(ctx context.Context, id order.ID, mode order.LockMode) (*order.Order, error) {
+	tx, ok := txFrom(ctx)
+	if !ok {
+		s.logger.Error(ctx, "order was not locked outside of a transaction")
+		return nil, fmt.Errorf("%w: lock requires a transaction", order.ErrNotFound)
+	}
+
+	clause, err := lockClause(mode)
+	if err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "order was not locked")
+		return nil, fmt.Errorf("%w: %w", order.ErrNotFound, err)
+	}
+
+	var version int
+	err = tx.QueryRowContext(ctx, selectStreamQuery+" FOR "+clause, id.String()).Scan(&version)
+	switch {
+	case err == nil:
+		return s.Get(ctx, id)
+	case lockNotAvailable(err):
+		s.logger.With(golog.Err(err)).Warn(ctx, "order stream was locked in the database")
+		return nil, order.ErrLocked
+	case errors.Is(err, sql.ErrNoRows) && mode == order.LockSkipLocked:
+		var exists bool
+		if existsErr := tx.QueryRowContext(ctx, selectStreamExistsQuery, id.String()).Scan(&exists); existsErr == nil && exists {
+			s.logger.Warn(ctx, "order stream was skipped as locked in the database")
+			return nil, order.ErrLocked
+		}
+	}
+
+	s.logger.With(golog.Err(err)).Error(ctx, "order stream was not read from the database")
+	return nil, order.ErrNotFound
+}
+
+// Add appends the events of the order not stored yet to its stream, if the version of the order matches the stream one
+// It runs within the transaction carried by the context if any, otherwise within its own
+// It increments the version of the order by the number of events appended, and marks them as stored,
+// both being restored if the transaction carried by the context is rolled back
+func (s *EventStore) Add(ctx context.Context, o *order.Order) error {
+	if tx, ok := txFrom(ctx); ok {
+		version, stored := o.Version, o.StoredEvents()
+		if err := s.add(ctx, tx, o); err != nil {
+			return err
+		}
+		o.MarkEventsStored()
+		// the order is left as it was before once the transaction is rolled back, so that adding it again stores it all
+		transaction.AfterRollback(ctx, func(context.Context) {
+			o.Version = version
+			o.UnmarkEventsStored(stored)
+		})
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "transaction was not started")
+		return order.ErrNotAdded
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	version := o.Version
+	if err := s.add(ctx, tx, o); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "transaction was not committed")
+		o.Version = version
+		return order.ErrNotAdded
+	}
+
+	o.MarkEventsStored()
+	return nil
+}
+
+// add appends the events of the order not stored yet and snapshots it when due, using the given transaction
+func (s *EventStore) add(ctx context.Context, tx *sql.Tx, o *order.Order) error {
+	events := o.UnstoredEvents()
+	if len(events) == 0 {
+		return s.unchanged(ctx, tx, o)
+	}
+
+	version := o.Version + len(events)
+	if err := saveStream(ctx, tx, o, version); err != nil {
+		if errors.Is(err, order.ErrConcurrentModification) {
+			s.logger.With(golog.Err(err)).Warn(ctx, "order was modified concurrently in the database")
+			return fmt.Errorf("%w: %w", order.ErrNotAdded, err)
+		}
+		if errors.Is(err, order.ErrDuplicateNumber) {
+			s.logger.With(golog.Err(err)).Warn(ctx, "order number was already taken in the database")
+			return fmt.Errorf("%w: %w", order.ErrNotAdded, err)
+		}
+		s.logger.With(golog.Err(err)).Error(ctx, "order stream was not saved in the database")
+		return order.ErrNotAdded
+	}
+
+	for i, e := range events {
+		payload, err := marshalEvent(e)
+		if err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order event was not encoded")
+			return order.ErrNotAdded
+		}
+
+		if _, err := tx.ExecContext(ctx, insertEventQuery, o.ID.String(), o.Version+i+1, e.EventName(), payload, e.OccurredAt()); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order event was not appended in the database")
+			return order.ErrNotAdded
+		}
+	}
+
+	if s.outbox {
+		if err := addToOutbox(ctx, tx, events); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order events were not inserted in the outbox")
+			return order.ErrNotAdded
+		}
+	}
+
+	if s.snapshotEvery > 0 && version/s.snapshotEvery > o.Version/s.snapshotEvery {
+		payload, err := json.Marshal(o)
+		if err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order snapshot was not encoded")
+			return order.ErrNotAdded
+		}
+
+		if _, err := tx.ExecContext(ctx, upsertSnapshotQuery, o.ID.String(), version, payload); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order snapshot was not saved in the database")
+			return order.ErrNotAdded
+		}
+	}
+
+	o.Version = version
+	return nil
+}
+
+// unchanged checks that an order without pending events still matches its stream, as there is nothing to append
+func (s *EventStore) unchanged(ctx context.Context, tx *sql.Tx, o *order.Order) error {
+	if o.Version == 0 {
+		s.logger.Error(ctx, "order without events was not added in the database")
+		return fmt.Errorf("%w: no events to append", order.ErrNotAdded)
+	}
+
+	var version int
+	if err := tx.QueryRowContext(ctx, selectStreamQuery, o.ID.String()).Scan(&version); err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "order stream was not read from the database")
+		return order.ErrNotAdded
+	}
+
+	if version != o.Version {
+		s.logger.Warn(ctx, "order was modified concurrently in the database")
+		return fmt.Errorf("%w: %w", order.ErrNotAdded, order.ErrConcurrentModification)
+	}
+
+	return nil
+}
+
+// saveStream creates the stream of an order never stored, otherwise moves it to the given version
+// only if it is still at the version of the order
+func saveStream(ctx context.Context, tx *sql.Tx, o *order.Order, version int) error {
+	if o.Version == 0 {
+		if _, err := tx.ExecContext(ctx, insertStreamQuery, o.ID.String(), o.Number.String(), version); err != nil {
+			switch uniqueViolation(err) {
+			case orderStreamsNumberKey:
+				return order.ErrDuplicateNumber
+			case orderStreamsPrimaryKey:
+				return order.ErrConcurrentModification
+			}
+			return err
+		}
+
+		return nil
+	}
+
+	res, err := tx.ExecContext(ctx, updateStreamQuery, o.ID.String(), o.Version, version)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return order.ErrConcurrentModification
+	}
+
+	return nil
+}
+
+// getEvents queries the events of the order stored after the given version, returning the version of the last one
+func getEvents(ctx context.Context, exec boil.ContextExecutor, id order.ID, after int) ([]order.Event, int, error) {
+	rows, err := exec.QueryContext(ctx, selectEventsQuery, id.String(), after)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		events []order.Event
+		last   int
+	)
+	for rows.Next() {
+		var (
+			name    string
+			payload []byte
+		)
+		if err := rows.Scan(&last, &name, &payload); err != nil {
+			return nil, 0, err
+		}
+
+		e, err := unmarshalEvent(name, payload)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		events = append(events, e)
+	}
+
+	return events, last, rows.Err()
+}
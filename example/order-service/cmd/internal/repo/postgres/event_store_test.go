@@ -0,0 +1,341 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	gologTest "github.com/damianopetrungaro/golog/test"
+	"github.com/google/uuid"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/repotest"
+)
+
+func TestEventStore_Add(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getEventStore(t, db, 0)
+
+	o := getPlacedOrder(t)
+	if err := o.ChangeShippingAddress(order.SystemClock{}, getRandomAddress(t)); err != nil {
+		t.Fatalf("could not change shipping address: %s", err)
+	}
+
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	if o.Version != 2 {
+		t.Fatalf("could not match version once added: %d", o.Version)
+	}
+
+	found, err := repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+
+	matchesOrder(t, o, found)
+	if found.Version != o.Version {
+		t.Fatalf("could not match version: %d", found.Version)
+	}
+
+	if n := countEventsHelper(t, db, o.ID); n != 2 {
+		t.Fatalf("could not match number of events: %d", n)
+	}
+
+	// the events already appended are not appended again
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order again: %s", err)
+	}
+
+	if o.Version != 2 {
+		t.Fatalf("could not match version once added again: %d", o.Version)
+	}
+
+	if n := countEventsHelper(t, db, o.ID); n != 2 {
+		t.Fatalf("could not match number of events once added again: %d", n)
+	}
+
+	if events := o.Events(); len(events) != 2 {
+		t.Fatalf("could not match events left to publish: %v", events)
+	}
+}
+
+func TestEventStore_Add_RolledBack(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getEventStore(t, db, 0)
+	transactor := NewTransactor(db, gologTest.NewNullLogger())
+
+	o := getPlacedOrder(t)
+	errRollback := errors.New("rollback")
+	err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := repo.Add(ctx, o); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("could not match error: %s", err)
+	}
+
+	if o.Version != 0 {
+		t.Fatalf("could not match version once rolled back: %d", o.Version)
+	}
+
+	if events := o.UnstoredEvents(); len(events) != 1 {
+		t.Fatalf("could not match events left to store once rolled back: %v", events)
+	}
+
+	// the events rolled back are appended again
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order once rolled back: %s", err)
+	}
+
+	if n := countEventsHelper(t, db, o.ID); n != 1 {
+		t.Fatalf("could not match number of events: %d", n)
+	}
+}
+
+func TestEventStore_Get(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getEventStore(t, db, 0)
+
+	if _, err := repo.Get(ctx, order.NewID()); !errors.Is(err, order.ErrNotFound) {
+		t.Fatalf("could not match error: %s", err)
+	}
+}
+
+func TestEventStore_Add_ConcurrentModification(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getEventStore(t, db, 0)
+
+	o := getPlacedOrder(t)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	if o.Version != 1 {
+		t.Fatalf("could not match version once added: %d", o.Version)
+	}
+
+	first, err := repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+
+	second, err := repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+
+	if err := first.MarkAsShipped(order.SystemClock{}); err != nil {
+		t.Fatalf("could not mark order as shipped: %s", err)
+	}
+
+	if err := repo.Add(ctx, first); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	if first.Version != 2 {
+		t.Fatalf("could not match version once updated: %d", first.Version)
+	}
+
+	if err := second.MarkAsShipped(order.SystemClock{}); err != nil {
+		t.Fatalf("could not mark order as shipped: %s", err)
+	}
+
+	if err := repo.Add(ctx, second); !errors.Is(err, order.ErrConcurrentModification) {
+		t.Fatalf("could not match error: %s", err)
+	}
+
+	o.PullEvents()
+	if err := repo.Add(ctx, o); !errors.Is(err, order.ErrConcurrentModification) {
+		t.Fatalf("could not match error of a stale order without events: %s", err)
+	}
+
+	duplicated, err := order.Place(order.SystemClock{}, o.ID, order.GenerateNumber(), o.PlacedBy, getRandomItems(t))
+	if err != nil {
+		t.Fatalf("could not place order: %s", err)
+	}
+
+	if err := repo.Add(ctx, duplicated); !errors.Is(err, order.ErrConcurrentModification) {
+		t.Fatalf("could not match error: %s", err)
+	}
+}
+
+func TestEventStore_Add_DuplicateNumber(t *testing.T) {
+	ctx := context.Background()
+	db := getDB(t)
+	repo := getEventStore(t, db, 0)
+
+	o := getPlacedOrder(t)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	duplicated, err := order.Place(order.SystemClock{}, order.NewID(), o.Number, order.UserID(uuid.New()), getRandomItems(t))
+	if err != nil {
+		t.Fatalf("could not place order: %s", err)
+	}
+
+	if err := repo.Add(ctx, duplicated); !errors.Is(err, order.ErrDuplicateNumber) {
+		t.Fatalf("could not match error: %s", err)
+	}
+}
+
+func TestEventStore_Snapshot(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getEventStore(t, db, 2)
+
+	o := getPlacedOrder(t)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	if v := snapshotVersionHelper(t, db, o.ID); v != 0 {
+		t.Fatalf("could not match order not snapshotted yet: %d", v)
+	}
+
+	for _, change := range []func(*order.Order) error{
+		func(o *order.Order) error { return o.ChangeShippingAddress(order.SystemClock{}, getRandomAddress(t)) },
+		func(o *order.Order) error { return o.MarkAsShipped(order.SystemClock{}) },
+	} {
+		o.PullEvents()
+		if err := change(o); err != nil {
+			t.Fatalf("could not change order: %s", err)
+		}
+
+		if err := repo.Add(ctx, o); err != nil {
+			t.Fatalf("could not add order: %s", err)
+		}
+	}
+
+	if v := snapshotVersionHelper(t, db, o.ID); v != 2 {
+		t.Fatalf("could not match snapshot version: %d", v)
+	}
+
+	found, err := repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+
+	matchesOrder(t, o, found)
+	if found.Version != 3 {
+		t.Fatalf("could not match version replayed from the snapshot: %d", found.Version)
+	}
+
+	if n := countEventsHelper(t, db, o.ID); n != 3 {
+		t.Fatalf("could not match full history kept: %d", n)
+	}
+}
+
+func TestEventStore_GetForUpdate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getEventStore(t, db, 0)
+	transactor := NewTransactor(db, gologTest.NewNullLogger())
+
+	o := getPlacedOrder(t)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	locked, release, done := make(chan struct{}), make(chan struct{}), make(chan error)
+	go func() {
+		done <- transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			found, err := repo.GetForUpdate(ctx, o.ID, order.LockWait)
+			if err != nil {
+				return err
+			}
+			matchesOrder(t, o, found)
+			close(locked)
+			<-release
+			return nil
+		})
+	}()
+
+	select {
+	case <-locked:
+	case err := <-done:
+		t.Fatalf("could not lock order: %s", err)
+	}
+
+	err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := repo.GetForUpdate(ctx, o.ID, order.LockNoWait)
+		return err
+	})
+	if !errors.Is(err, order.ErrLocked) {
+		t.Fatalf("could not match error: %s", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("could not release lock: %s", err)
+	}
+}
+
+func TestEventStore_Contract(t *testing.T) {
+	// snapshots are taken every other event, so that orders are read from both snapshots and events
+	repotest.Run(t, func(t *testing.T) order.Repo {
+		return getEventStore(t, getDB(t), 2)
+	})
+}
+
+func getEventStore(t *testing.T, db *sql.DB, snapshotEvery int) *EventStore {
+	t.Helper()
+
+	return NewEventStore(db, snapshotEvery, gologTest.NewNullLogger())
+}
+
+func countEventsHelper(t *testing.T, db *sql.DB, id order.ID) int {
+	t.Helper()
+
+	var n int
+	if err := db.QueryRow(`SELECT count(*) FROM order_events WHERE order_id = $1`, id.String()).Scan(&n); err != nil {
+		t.Fatalf("could not count order events: %s", err)
+	}
+
+	return n
+}
+
+func snapshotVersionHelper(t *testing.T, db *sql.DB, id order.ID) int {
+	t.Helper()
+
+	var v int
+	err := db.QueryRow(`SELECT version FROM order_snapshots WHERE order_id = $1`, id.String()).Scan(&v)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("could not query order snapshot: %s", err)
+	}
+
+	return v
+}
@@ -24,52 +24,92 @@ import (
 
 // Order is an object representing the database table.
 type Order struct {
-	ID          string    `boil:"id" json:"id" toml:"id" yaml:"id"`
-	Number      string    `boil:"number" json:"number" toml:"number" yaml:"number"`
-	Status      string    `boil:"status" json:"status" toml:"status" yaml:"status"`
-	PlacedBy    string    `boil:"placed_by" json:"placed_by" toml:"placed_by" yaml:"placed_by"`
-	PlacedAt    time.Time `boil:"placed_at" json:"placed_at" toml:"placed_at" yaml:"placed_at"`
-	ShippedAt   null.Time `boil:"shipped_at" json:"shipped_at,omitempty" toml:"shipped_at" yaml:"shipped_at,omitempty"`
-	DeliveredAt null.Time `boil:"delivered_at" json:"delivered_at,omitempty" toml:"delivered_at" yaml:"delivered_at,omitempty"`
+	ID                 string      `boil:"id" json:"id" toml:"id" yaml:"id"`
+	Number             string      `boil:"number" json:"number" toml:"number" yaml:"number"`
+	Status             string      `boil:"status" json:"status" toml:"status" yaml:"status"`
+	PlacedBy           string      `boil:"placed_by" json:"placed_by" toml:"placed_by" yaml:"placed_by"`
+	PlacedAt           time.Time   `boil:"placed_at" json:"placed_at" toml:"placed_at" yaml:"placed_at"`
+	ShippedAt          null.Time   `boil:"shipped_at" json:"shipped_at,omitempty" toml:"shipped_at" yaml:"shipped_at,omitempty"`
+	DeliveredAt        null.Time   `boil:"delivered_at" json:"delivered_at,omitempty" toml:"delivered_at" yaml:"delivered_at,omitempty"`
+	CancelledAt        null.Time   `boil:"cancelled_at" json:"cancelled_at,omitempty" toml:"cancelled_at" yaml:"cancelled_at,omitempty"`
+	CancellationReason null.String `boil:"cancellation_reason" json:"cancellation_reason,omitempty" toml:"cancellation_reason" yaml:"cancellation_reason,omitempty"`
+	Version            int         `boil:"version" json:"version" toml:"version" yaml:"version"`
+	ShippingRecipient  null.String `boil:"shipping_recipient" json:"shipping_recipient,omitempty" toml:"shipping_recipient" yaml:"shipping_recipient,omitempty"`
+	ShippingLines      null.String `boil:"shipping_lines" json:"shipping_lines,omitempty" toml:"shipping_lines" yaml:"shipping_lines,omitempty"`
+	ShippingCity       null.String `boil:"shipping_city" json:"shipping_city,omitempty" toml:"shipping_city" yaml:"shipping_city,omitempty"`
+	ShippingPostalCode null.String `boil:"shipping_postal_code" json:"shipping_postal_code,omitempty" toml:"shipping_postal_code" yaml:"shipping_postal_code,omitempty"`
+	ShippingCountry    null.String `boil:"shipping_country" json:"shipping_country,omitempty" toml:"shipping_country" yaml:"shipping_country,omitempty"`
 
 	R *orderR `boil:"-" json:"-" toml:"-" yaml:"-"`
 	L orderL  `boil:"-" json:"-" toml:"-" yaml:"-"`
 }
 
 var OrderColumns = struct {
-	ID          string
-	Number      string
-	Status      string
-	PlacedBy    string
-	PlacedAt    string
-	ShippedAt   string
-	DeliveredAt string
+	ID                 string
+	Number             string
+	Status             string
+	PlacedBy           string
+	PlacedAt           string
+	ShippedAt          string
+	DeliveredAt        string
+	CancelledAt        string
+	CancellationReason string
+	Version            string
+	ShippingRecipient  string
+	ShippingLines      string
+	ShippingCity       string
+	ShippingPostalCode string
+	ShippingCountry    string
 }{
-	ID:          "id",
-	Number:      "number",
-	Status:      "status",
-	PlacedBy:    "placed_by",
-	PlacedAt:    "placed_at",
-	ShippedAt:   "shipped_at",
-	DeliveredAt: "delivered_at",
+	ID:                 "id",
+	Number:             "number",
+	Status:             "status",
+	PlacedBy:           "placed_by",
+	PlacedAt:           "placed_at",
+	ShippedAt:          "shipped_at",
+	DeliveredAt:        "delivered_at",
+	CancelledAt:        "cancelled_at",
+	CancellationReason: "cancellation_reason",
+	Version:            "version",
+	ShippingRecipient:  "shipping_recipient",
+	ShippingLines:      "shipping_lines",
+	ShippingCity:       "shipping_city",
+	ShippingPostalCode: "shipping_postal_code",
+	ShippingCountry:    "shipping_country",
 }
 
 var OrderTableColumns = struct {
-	ID          string
-	Number      string
-	Status      string
-	PlacedBy    string
-	PlacedAt    string
-	ShippedAt   string
-	DeliveredAt string
+	ID                 string
+	Number             string
+	Status             string
+	PlacedBy           string
+	PlacedAt           string
+	ShippedAt          string
+	DeliveredAt        string
+	CancelledAt        string
+	CancellationReason string
+	Version            string
+	ShippingRecipient  string
+	ShippingLines      string
+	ShippingCity       string
+	ShippingPostalCode string
+	ShippingCountry    string
 }{
-	ID:          "orders.id",
-	Number:      "orders.number",
-	Status:      "orders.status",
-	PlacedBy:    "orders.placed_by",
-	PlacedAt:    "orders.placed_at",
-	ShippedAt:   "orders.shipped_at",
-	DeliveredAt: "orders.delivered_at",
+	ID:                 "orders.id",
+	Number:             "orders.number",
+	Status:             "orders.status",
+	PlacedBy:           "orders.placed_by",
+	PlacedAt:           "orders.placed_at",
+	ShippedAt:          "orders.shipped_at",
+	DeliveredAt:        "orders.delivered_at",
+	CancelledAt:        "orders.cancelled_at",
+	CancellationReason: "orders.cancellation_reason",
+	Version:            "orders.version",
+	ShippingRecipient:  "orders.shipping_recipient",
+	ShippingLines:      "orders.shipping_lines",
+	ShippingCity:       "orders.shipping_city",
+	ShippingPostalCode: "orders.shipping_postal_code",
+	ShippingCountry:    "orders.shipping_country",
 }
 
 // Generated where
@@ -142,22 +182,99 @@ func (w whereHelpernull_Time) GTE(x null.Time) qm.QueryMod {
 func (w whereHelpernull_Time) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
 func (w whereHelpernull_Time) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
 
+type whereHelpernull_String struct{ field string }
+
+func (w whereHelpernull_String) EQ(x null.String) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, false, x)
+}
+func (w whereHelpernull_String) NEQ(x null.String) qm.QueryMod {
+	return qmhelper.WhereNullEQ(w.field, true, x)
+}
+func (w whereHelpernull_String) LT(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LT, x)
+}
+func (w whereHelpernull_String) LTE(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.LTE, x)
+}
+func (w whereHelpernull_String) GT(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GT, x)
+}
+func (w whereHelpernull_String) GTE(x null.String) qm.QueryMod {
+	return qmhelper.Where(w.field, qmhelper.GTE, x)
+}
+func (w whereHelpernull_String) IN(slice []string) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelpernull_String) NIN(slice []string) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
+func (w whereHelpernull_String) IsNull() qm.QueryMod    { return qmhelper.WhereIsNull(w.field) }
+func (w whereHelpernull_String) IsNotNull() qm.QueryMod { return qmhelper.WhereIsNotNull(w.field) }
+
+type whereHelperint struct{ field string }
+
+func (w whereHelperint) EQ(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.EQ, x) }
+func (w whereHelperint) NEQ(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.NEQ, x) }
+func (w whereHelperint) LT(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.LT, x) }
+func (w whereHelperint) LTE(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.LTE, x) }
+func (w whereHelperint) GT(x int) qm.QueryMod  { return qmhelper.Where(w.field, qmhelper.GT, x) }
+func (w whereHelperint) GTE(x int) qm.QueryMod { return qmhelper.Where(w.field, qmhelper.GTE, x) }
+func (w whereHelperint) IN(slice []int) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereIn(fmt.Sprintf("%s IN ?", w.field), values...)
+}
+func (w whereHelperint) NIN(slice []int) qm.QueryMod {
+	values := make([]interface{}, 0, len(slice))
+	for _, value := range slice {
+		values = append(values, value)
+	}
+	return qm.WhereNotIn(fmt.Sprintf("%s NOT IN ?", w.field), values...)
+}
+
 var OrderWhere = struct {
-	ID          whereHelperstring
-	Number      whereHelperstring
-	Status      whereHelperstring
-	PlacedBy    whereHelperstring
-	PlacedAt    whereHelpertime_Time
-	ShippedAt   whereHelpernull_Time
-	DeliveredAt whereHelpernull_Time
+	ID                 whereHelperstring
+	Number             whereHelperstring
+	Status             whereHelperstring
+	PlacedBy           whereHelperstring
+	PlacedAt           whereHelpertime_Time
+	ShippedAt          whereHelpernull_Time
+	DeliveredAt        whereHelpernull_Time
+	CancelledAt        whereHelpernull_Time
+	CancellationReason whereHelpernull_String
+	Version            whereHelperint
+	ShippingRecipient  whereHelpernull_String
+	ShippingLines      whereHelpernull_String
+	ShippingCity       whereHelpernull_String
+	ShippingPostalCode whereHelpernull_String
+	ShippingCountry    whereHelpernull_String
 }{
-	ID:          whereHelperstring{field: "\"orders\".\"id\""},
-	Number:      whereHelperstring{field: "\"orders\".\"number\""},
-	Status:      whereHelperstring{field: "\"orders\".\"status\""},
-	PlacedBy:    whereHelperstring{field: "\"orders\".\"placed_by\""},
-	PlacedAt:    whereHelpertime_Time{field: "\"orders\".\"placed_at\""},
-	ShippedAt:   whereHelpernull_Time{field: "\"orders\".\"shipped_at\""},
-	DeliveredAt: whereHelpernull_Time{field: "\"orders\".\"delivered_at\""},
+	ID:                 whereHelperstring{field: "\"orders\".\"id\""},
+	Number:             whereHelperstring{field: "\"orders\".\"number\""},
+	Status:             whereHelperstring{field: "\"orders\".\"status\""},
+	PlacedBy:           whereHelperstring{field: "\"orders\".\"placed_by\""},
+	PlacedAt:           whereHelpertime_Time{field: "\"orders\".\"placed_at\""},
+	ShippedAt:          whereHelpernull_Time{field: "\"orders\".\"shipped_at\""},
+	DeliveredAt:        whereHelpernull_Time{field: "\"orders\".\"delivered_at\""},
+	CancelledAt:        whereHelpernull_Time{field: "\"orders\".\"cancelled_at\""},
+	CancellationReason: whereHelpernull_String{field: "\"orders\".\"cancellation_reason\""},
+	Version:            whereHelperint{field: "\"orders\".\"version\""},
+	ShippingRecipient:  whereHelpernull_String{field: "\"orders\".\"shipping_recipient\""},
+	ShippingLines:      whereHelpernull_String{field: "\"orders\".\"shipping_lines\""},
+	ShippingCity:       whereHelpernull_String{field: "\"orders\".\"shipping_city\""},
+	ShippingPostalCode: whereHelpernull_String{field: "\"orders\".\"shipping_postal_code\""},
+	ShippingCountry:    whereHelpernull_String{field: "\"orders\".\"shipping_country\""},
 }
 
 // OrderRels is where relationship names are stored.
@@ -177,9 +294,9 @@ func (*orderR) NewStruct() *orderR {
 type orderL struct{}
 
 var (
-	orderAllColumns            = []string{"id", "number", "status", "placed_by", "placed_at", "shipped_at", "delivered_at"}
+	orderAllColumns            = []string{"id", "number", "status", "placed_by", "placed_at", "shipped_at", "delivered_at", "cancelled_at", "cancellation_reason", "version", "shipping_recipient", "shipping_lines", "shipping_city", "shipping_postal_code", "shipping_country"}
 	orderColumnsWithoutDefault = []string{"id", "number", "status", "placed_by", "placed_at"}
-	orderColumnsWithDefault    = []string{"shipped_at", "delivered_at"}
+	orderColumnsWithDefault    = []string{"shipped_at", "delivered_at", "cancelled_at", "cancellation_reason", "version", "shipping_recipient", "shipping_lines", "shipping_city", "shipping_postal_code", "shipping_country"}
 	orderPrimaryKeyColumns     = []string{"id"}
 	orderGeneratedColumns      = []string{}
 )
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+	"github.com/organization/order-service"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+const (
+	deleteItemsQuery = `DELETE FROM order_items WHERE order_id = $1`
+
+	insertItemQuery = `INSERT INTO order_items (order_id, position, sku, quantity, unit_price_amount, unit_price_currency) VALUES ($1, $2, $3, $4, $5, $6)`
+
+	selectItemsQuery = `SELECT sku, quantity, unit_price_amount, unit_price_currency FROM order_items WHERE order_id = $1 ORDER BY position`
+
+	selectItemsOfQuery = `SELECT order_id, sku, quantity, unit_price_amount, unit_price_currency FROM order_items WHERE order_id = ANY($1) ORDER BY order_id, position`
+)
+
+// saveItems replaces the items of an order using the given executor
+func saveItems(ctx context.Context, exec boil.ContextExecutor, id order.ID, items []order.LineItem) error {
+	if _, err := exec.ExecContext(ctx, deleteItemsQuery, id.String()); err != nil {
+		return err
+	}
+
+	for pos, i := range items {
+		if _, err := exec.ExecContext(ctx, insertItemQuery, id.String(), pos, i.SKU.String(), i.Quantity, i.UnitPrice.Amount(), i.UnitPrice.Currency()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getItems queries the items of an order using the given executor
+func getItems(ctx context.Context, exec boil.ContextExecutor, id order.ID) ([]order.LineItem, error) {
+	rows, err := exec.QueryContext(ctx, selectItemsQuery, id.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []order.LineItem
+	for rows.Next() {
+		var (
+			i        order.LineItem
+			sku      string
+			amount   int64
+			currency order.Currency
+		)
+		if err := rows.Scan(&sku, &i.Quantity, &amount, &currency); err != nil {
+			return nil, err
+		}
+
+		i.SKU = order.SKU(sku)
+		i.UnitPrice = order.NewMoney(amount, currency)
+		items = append(items, i)
+	}
+
+	return items, rows.Err()
+}
+
+// getItemsOf queries the items of many orders at once using the given executor, grouped by order
+func getItemsOf(ctx context.Context, exec boil.ContextExecutor, ids []order.ID) (map[order.ID][]order.LineItem, error) {
+	raw := make([]string, len(ids))
+	for i, id := range ids {
+		raw[i] = id.String()
+	}
+
+	rows, err := exec.QueryContext(ctx, selectItemsOfQuery, pq.Array(raw))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := map[order.ID][]order.LineItem{}
+	for rows.Next() {
+		var (
+			i        order.LineItem
+			id       string
+			sku      string
+			amount   int64
+			currency order.Currency
+		)
+		if err := rows.Scan(&id, &sku, &i.Quantity, &amount, &currency); err != nil {
+			return nil, err
+		}
+
+		oID, err := order.ParseID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		i.SKU = order.SKU(sku)
+		i.UnitPrice = order.NewMoney(amount, currency)
+		items[oID] = append(items[oID], i)
+	}
+
+	return items, rows.Err()
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/postgres/internal"
+	"github.com/volatiletech/null/v8"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+var (
+	_ order.Lister = &Postgres{}
+)
+
+// List queries a page of the orders matching the filter, from the most recently placed
+// Pages are read by keyset from the cursor, so that their cost does not grow with the number of orders before them
+func (p *Postgres) List(ctx context.Context, f order.Filter, c order.Cursor) (order.Page, error) {
+	if err := f.Validate(); err != nil {
+		return order.Page{}, fmt.Errorf("%w: %w", order.ErrNotListed, err)
+	}
+
+	mods := append(filterMods(f), cursorMods(c)...)
+	mods = append(mods, qm.Limit(f.Size()+1))
+	models, err := internal.Orders(mods...).All(ctx, executor(ctx, p.db))
+	if err != nil {
+		p.logger.With(golog.Err(err)).Error(ctx, "orders were not read from the database")
+		return order.Page{}, order.ErrNotListed
+	}
+
+	orders, err := p.fromOrderModels(ctx, models)
+	if err != nil {
+		return order.Page{}, order.ErrNotListed
+	}
+
+	return order.NewPage(orders, f.Size(), c), nil
+}
+
+// cursorMods returns the query mods selecting and sorting the orders following the cursor in its direction
+func cursorMods(c order.Cursor) []qm.QueryMod {
+	key := "(" + internal.OrderColumns.PlacedAt + ", " + internal.OrderColumns.ID + ")"
+	if c.Direction == order.Backward {
+		mods := []qm.QueryMod{qm.OrderBy(internal.OrderColumns.PlacedAt + " ASC, " + internal.OrderColumns.ID + " ASC")}
+		if !c.IsZero() {
+			mods = append(mods, qm.Where(key+" > (?, ?)", c.PlacedAt, c.ID.String()))
+		}
+		return mods
+	}
+
+	mods := []qm.QueryMod{qm.OrderBy(internal.OrderColumns.PlacedAt + " DESC, " + internal.OrderColumns.ID + " DESC")}
+	if !c.IsZero() {
+		mods = append(mods, qm.Where(key+" < (?, ?)", c.PlacedAt, c.ID.String()))
+	}
+
+	return mods
+}
+
+// fromOrderModels maps the models to orders, loading the items of all of them at once
+func (p *Postgres) fromOrderModels(ctx context.Context, models internal.OrderSlice) ([]*order.Order, error) {
+	orders := make([]*order.Order, len(models))
+	ids := make([]order.ID, len(models))
+	for i, model := range models {
+		o := fromOrderModel(model)
+		address, err := fromAddressModel(model)
+		if err != nil {
+			p.logger.With(golog.Err(err)).Error(ctx, "order shipping address was not read from the database")
+			return nil, err
+		}
+		o.ShippingAddress = address
+
+		orders[i] = o
+		ids[i] = o.ID
+	}
+
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	items, err := getItemsOf(ctx, executor(ctx, p.db), ids)
+	if err != nil {
+		p.logger.With(golog.Err(err)).Error(ctx, "order items were not read from the database")
+		return nil, err
+	}
+
+	for _, o := range orders {
+		o.Items = items[o.ID]
+	}
+
+	return orders, nil
+}
+
+// filterMods returns the query mods selecting the orders matching the filter
+func filterMods(f order.Filter) []qm.QueryMod {
+	var mods []qm.QueryMod
+	if !f.PlacedBy.IsZero() {
+		mods = append(mods, internal.OrderWhere.PlacedBy.EQ(f.PlacedBy.String()))
+	}
+
+	if len(f.Statuses) > 0 {
+		statuses := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			statuses[i] = s.String()
+		}
+		mods = append(mods, internal.OrderWhere.Status.IN(statuses))
+	}
+
+	if !f.PlacedAt.From.IsZero() {
+		mods = append(mods, internal.OrderWhere.PlacedAt.GTE(f.PlacedAt.From))
+	}
+	if !f.PlacedAt.To.IsZero() {
+		mods = append(mods, internal.OrderWhere.PlacedAt.LT(f.PlacedAt.To))
+	}
+
+	mods = append(mods, nullTimeRangeMods(internal.OrderWhere.ShippedAt, f.ShippedAt)...)
+	mods = append(mods, nullTimeRangeMods(internal.OrderWhere.DeliveredAt, f.DeliveredAt)...)
+
+	return mods
+}
+
+// nullTimeRangeMods returns the query mods selecting the times in the range
+// a non-zero range never matches the orders not shipped or delivered yet, stored either as NULL or as the zero time
+func nullTimeRangeMods(w interface {
+	GT(null.Time) qm.QueryMod
+	GTE(null.Time) qm.QueryMod
+	LT(null.Time) qm.QueryMod
+}, r order.TimeRange) []qm.QueryMod {
+	if r.IsZero() {
+		return nil
+	}
+
+	mods := []qm.QueryMod{w.GT(null.TimeFrom(time.Time{}))}
+	if !r.From.IsZero() {
+		mods = append(mods, w.GTE(null.TimeFrom(r.From)))
+	}
+	if !r.To.IsZero() {
+		mods = append(mods, w.LT(null.TimeFrom(r.To)))
+	}
+
+	return mods
+}
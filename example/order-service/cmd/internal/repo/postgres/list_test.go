@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/organization/order-service"
+)
+
+func TestPostgres_List(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getPostgres(t, db)
+
+	uID := order.UserID(uuid.New())
+	placedAt := time.Date(2023, time.March, 21, 9, 0, 0, 0, time.UTC)
+
+	var orders []*order.Order
+	for i := 0; i < 3; i++ {
+		o := getPlacedOrder(t)
+		o.PlacedBy = uID
+		o.PlacedAt = placedAt.Add(time.Duration(i) * time.Hour)
+		if i == 2 {
+			o.Status = order.Shipped
+			o.ShippedAt = placedAt.Add(24 * time.Hour)
+		}
+		addOrderHelper(t, db, o)
+		orders = append(orders, o)
+	}
+	addOrderHelper(t, db, getRandomOrder(t))
+
+	t.Run("placed by", func(t *testing.T) {
+		page, err := repo.List(ctx, order.Filter{PlacedBy: uID}, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		got := page.Orders
+
+		if len(got) != 3 {
+			t.Fatalf("could not match number of orders: %d", len(got))
+		}
+
+		for i, o := range got {
+			matchesOrder(t, orders[len(orders)-1-i], o)
+		}
+	})
+
+	t.Run("status", func(t *testing.T) {
+		page, err := repo.List(ctx, order.Filter{PlacedBy: uID, Statuses: []order.Status{order.Shipped}}, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		got := page.Orders
+
+		if len(got) != 1 || got[0].ID != orders[2].ID {
+			t.Fatalf("could not match shipped orders: %v", got)
+		}
+	})
+
+	t.Run("placed at", func(t *testing.T) {
+		page, err := repo.List(ctx, order.Filter{PlacedBy: uID, PlacedAt: order.TimeRange{From: placedAt, To: placedAt.Add(2 * time.Hour)}}, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		got := page.Orders
+
+		if len(got) != 2 || got[0].ID != orders[1].ID || got[1].ID != orders[0].ID {
+			t.Fatalf("could not match orders placed in range: %v", got)
+		}
+	})
+
+	t.Run("shipped at", func(t *testing.T) {
+		page, err := repo.List(ctx, order.Filter{PlacedBy: uID, ShippedAt: order.TimeRange{To: placedAt.Add(48 * time.Hour)}}, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		got := page.Orders
+
+		if len(got) != 1 || got[0].ID != orders[2].ID {
+			t.Fatalf("could not match orders shipped in range: %v", got)
+		}
+	})
+
+	t.Run("limit", func(t *testing.T) {
+		page, err := repo.List(ctx, order.Filter{PlacedBy: uID, Limit: 1}, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		got := page.Orders
+
+		if len(got) != 1 || got[0].ID != orders[2].ID {
+			t.Fatalf("could not match limited orders: %v", got)
+		}
+	})
+
+	t.Run("not valid", func(t *testing.T) {
+		if _, err := repo.List(ctx, order.Filter{Limit: order.MaxListLimit + 1}, order.Cursor{}); !errors.Is(err, order.ErrFilterNotValid) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+}
+
+func TestPostgres_List_Pages(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getPostgres(t, db)
+
+	uID := order.UserID(uuid.New())
+	placedAt := time.Date(2023, time.March, 21, 9, 0, 0, 0, time.UTC)
+
+	// orders are sorted as they are listed, the last two sharing the same placed at time
+	var orders []*order.Order
+	for _, h := range []time.Duration{0, 1, 2, 3, 3} {
+		o := getPlacedOrder(t)
+		o.PlacedBy = uID
+		o.PlacedAt = placedAt.Add(-h * time.Hour)
+		orders = append(orders, o)
+	}
+	if bytes.Compare(orders[3].ID[:], orders[4].ID[:]) < 0 {
+		orders[3], orders[4] = orders[4], orders[3]
+	}
+	for _, o := range orders {
+		addOrderHelper(t, db, o)
+	}
+
+	f := order.Filter{PlacedBy: uID, Limit: 2}
+	list := func(t *testing.T, c order.Cursor, want ...*order.Order) order.Page {
+		t.Helper()
+
+		p, err := repo.List(ctx, f, c)
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		if len(p.Orders) != len(want) {
+			t.Fatalf("could not match number of orders: got %d, want %d", len(p.Orders), len(want))
+		}
+
+		for i, o := range p.Orders {
+			matchesOrder(t, want[i], o)
+		}
+
+		return p
+	}
+
+	t.Run("forward", func(t *testing.T) {
+		first := list(t, order.Cursor{}, orders[0], orders[1])
+		if !first.Previous.IsZero() || first.Next.IsZero() {
+			t.Fatalf("could not match cursors of the first page: %v", first)
+		}
+
+		second := list(t, first.Next, orders[2], orders[3])
+		if second.Previous.IsZero() || second.Next.IsZero() {
+			t.Fatalf("could not match cursors of the second page: %v", second)
+		}
+
+		last := list(t, second.Next, orders[4])
+		if last.Previous.IsZero() || !last.Next.IsZero() {
+			t.Fatalf("could not match cursors of the last page: %v", last)
+		}
+	})
+
+	t.Run("backward", func(t *testing.T) {
+		last := list(t, order.Cursor{Direction: order.Backward}, orders[3], orders[4])
+		if last.Previous.IsZero() || !last.Next.IsZero() {
+			t.Fatalf("could not match cursors of the last page: %v", last)
+		}
+
+		second := list(t, last.Previous, orders[1], orders[2])
+		if second.Previous.IsZero() || second.Next.IsZero() {
+			t.Fatalf("could not match cursors of the second page: %v", second)
+		}
+
+		first := list(t, second.Previous, orders[0])
+		if !first.Previous.IsZero() || first.Next.IsZero() {
+			t.Fatalf("could not match cursors of the first page: %v", first)
+		}
+
+		list(t, first.Next, orders[1], orders[2])
+	})
+
+	t.Run("encoded", func(t *testing.T) {
+		codec, err := order.NewCursorCodec(bytes.Repeat([]byte("k"), order.MinCursorKeyLength))
+		if err != nil {
+			t.Fatalf("could not create cursor codec: %s", err)
+		}
+
+		first := list(t, order.Cursor{}, orders[0], orders[1])
+		c, err := codec.Decode(codec.Encode(first.Next, f), f)
+		if err != nil {
+			t.Fatalf("could not decode cursor: %s", err)
+		}
+
+		list(t, c, orders[2], orders[3])
+	})
+
+	t.Run("stable", func(t *testing.T) {
+		first := list(t, order.Cursor{}, orders[0], orders[1])
+
+		newer := getPlacedOrder(t)
+		newer.PlacedBy = uID
+		newer.PlacedAt = placedAt.Add(time.Hour)
+		addOrderHelper(t, db, newer)
+
+		list(t, first.Next, orders[2], orders[3])
+	})
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/lib/pq"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/postgres/internal"
+	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+)
+
+var (
+	_ order.Locker = &Postgres{}
+)
+
+// GetForUpdate queries an order and its items from the database, locking its row until the end of the transaction
+// carried by the context, so that concurrent use cases cannot change the order in the meantime
+// It returns ErrLocked when the mode does not wait for the lock held by another transaction
+func (p *Postgres) GetForUpdate(ctx context.Context, id order.ID, mode order.LockMode) (*order.Order, error) {
+	tx, ok := txFrom(ctx)
+	if !ok {
+		p.logger.Error(ctx, "order was not locked outside of a transaction")
+		return nil, fmt.Errorf("%w: lock requires a transaction", order.ErrNotFound)
+	}
+
+	clause, err := lockClause(mode)
+	if err != nil {
+		p.logger.With(golog.Err(err)).Error(ctx, "order was not locked")
+		return nil, fmt.Errorf("%w: %w", order.ErrNotFound, err)
+	}
+
+	model, err := internal.Orders(
+		qm.Where("id=?", id.String()),
+		qm.For(clause),
+	).One(ctx, tx)
+	switch {
+	case err == nil:
+		return p.load(ctx, tx, model)
+	case lockNotAvailable(err):
+		p.logger.With(golog.Err(err)).Warn(ctx, "order was locked in the database")
+		return nil, order.ErrLocked
+	case errors.Is(err, sql.ErrNoRows) && mode == order.LockSkipLocked:
+		// a skipped row cannot be told apart from a missing one without looking it up
+		if exists, existsErr := internal.OrderExists(ctx, tx, id.String()); existsErr == nil && exists {
+			p.logger.Warn(ctx, "order was skipped as locked in the database")
+			return nil, order.ErrLocked
+		}
+	}
+
+	p.logger.With(golog.Err(err)).Error(ctx, "order was not read from the database")
+	return nil, order.ErrNotFound
+}
+
+// lockClause returns the locking clause of the mode
+func lockClause(mode order.LockMode) (string, error) {
+	switch mode {
+	case order.LockWait:
+		return "UPDATE", nil
+	case order.LockNoWait:
+		return "UPDATE NOWAIT", nil
+	case order.LockSkipLocked:
+		return "UPDATE SKIP LOCKED", nil
+	default:
+		return "", fmt.Errorf("%w: %s", order.ErrLockModeNotParsed, mode)
+	}
+}
+
+// lockNotAvailable reports whether err was raised by a lock that could not be acquired right away
+func lockNotAvailable(err error) bool {
+	const lockNotAvailable = "55P03"
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == lockNotAvailable
+}
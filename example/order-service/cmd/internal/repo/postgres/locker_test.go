@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gologTest "github.com/damianopetrungaro/golog/test"
+	"github.com/organization/order-service"
+)
+
+func TestPostgres_GetForUpdate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getPostgres(t, db)
+	transactor := NewTransactor(db, gologTest.NewNullLogger())
+
+	o := getRandomOrder(t)
+	addOrderHelper(t, db, o)
+
+	// lock holds the lock of the order until the returned function is called
+	lock := func(t *testing.T) func() {
+		t.Helper()
+
+		locked, release, done := make(chan struct{}), make(chan struct{}), make(chan error)
+		go func() {
+			done <- transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				if _, err := repo.GetForUpdate(ctx, o.ID, order.LockWait); err != nil {
+					return err
+				}
+				close(locked)
+				<-release
+				return nil
+			})
+		}()
+
+		select {
+		case <-locked:
+		case err := <-done:
+			t.Fatalf("could not lock order: %s", err)
+		}
+
+		return func() {
+			close(release)
+			if err := <-done; err != nil {
+				t.Fatalf("could not release lock: %s", err)
+			}
+		}
+	}
+
+	t.Run("locked", func(t *testing.T) {
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			found, err := repo.GetForUpdate(ctx, o.ID, order.LockNoWait)
+			if err != nil {
+				return err
+			}
+
+			matchesOrder(t, o, found)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("could not lock order: %s", err)
+		}
+	})
+
+	t.Run("outside of a transaction", func(t *testing.T) {
+		if _, err := repo.GetForUpdate(ctx, o.ID, order.LockWait); !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := repo.GetForUpdate(ctx, order.NewID(), order.LockSkipLocked)
+			return err
+		})
+		if !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+
+	for _, mode := range []order.LockMode{order.LockNoWait, order.LockSkipLocked} {
+		t.Run(mode.String(), func(t *testing.T) {
+			release := lock(t)
+			t.Cleanup(release)
+
+			err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				_, err := repo.GetForUpdate(ctx, o.ID, mode)
+				return err
+			})
+			if !errors.Is(err, order.ErrLocked) {
+				t.Fatalf("could not match error: %s", err)
+			}
+		})
+	}
+
+	t.Run("wait", func(t *testing.T) {
+		release := lock(t)
+
+		got := make(chan error)
+		go func() {
+			got <- transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				_, err := repo.GetForUpdate(ctx, o.ID, order.LockWait)
+				return err
+			})
+		}()
+
+		select {
+		case err := <-got:
+			t.Fatalf("could not match lock waited for: %s", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		release()
+
+		if err := <-got; err != nil {
+			t.Fatalf("could not lock order once released: %s", err)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/organization/order-service"
+)
+
+var (
+	_ order.NumberAllocator = &NumberAllocator{}
+)
+
+// nextNumberQuery increments the sequence of the tenant, creating it on its first allocation
+// The row lock taken by the upsert serializes concurrent allocations of the same tenant
+const nextNumberQuery = `INSERT INTO order_number_sequences (tenant, value) VALUES ($1, 1)
+ON CONFLICT (tenant) DO UPDATE SET value = order_number_sequences.value + 1
+RETURNING value`
+
+// NumberAllocator represents the database table holding the order number sequence of every tenant
+// A number is lost when the order it was allocated for is never stored, so sequences may have gaps,
+// unless it is allocated within the transaction storing the order
+type NumberAllocator struct {
+	db      *sql.DB
+	formats order.NumberFormats
+	logger  golog.Logger
+}
+
+// NewNumberAllocator returns a database integration layer implementing order.NumberAllocator
+func NewNumberAllocator(db *sql.DB, formats order.NumberFormats, logger golog.Logger) *NumberAllocator {
+	return &NumberAllocator{
+		db:      db,
+		formats: formats,
+		logger:  logger,
+	}
+}
+
+// Allocate increments the sequence of the tenant and returns it formatted as a Number
+// It runs within the transaction carried by the context if any, holding the sequence of the tenant until it ends
+func (a *NumberAllocator) Allocate(ctx context.Context, tenant order.Tenant, at time.Time) (order.Number, error) {
+	if _, ok := a.formats[tenant]; !ok {
+		return "", fmt.Errorf("%w: unknown tenant %q", order.ErrNumberNotAllocated, tenant)
+	}
+
+	var seq uint64
+	if err := executor(ctx, a.db).QueryRowContext(ctx, nextNumberQuery, tenant.String()).Scan(&seq); err != nil {
+		a.logger.With(golog.Err(err)).Error(ctx, "order number sequence was not incremented in the database")
+		return "", order.ErrNumberNotAllocated
+	}
+
+	n, err := a.formats.Format(tenant, at, seq)
+	if err != nil {
+		a.logger.With(golog.Err(err)).Error(ctx, "order number was not formatted")
+		return "", fmt.Errorf("%w: %w", order.ErrNumberNotAllocated, err)
+	}
+
+	return n, nil
+}
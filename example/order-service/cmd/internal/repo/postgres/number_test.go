@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gologTest "github.com/damianopetrungaro/golog/test"
+	"github.com/google/uuid"
+	"github.com/organization/order-service"
+)
+
+func TestNumberAllocator_Allocate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	tenant := order.Tenant("tenant-" + uuid.NewString()[:8])
+	format := order.NumberFormat{Prefix: "TST", SequenceLength: 6}
+	allocator := NewNumberAllocator(db, order.NumberFormats{tenant: format}, gologTest.NewNullLogger())
+
+	t.Run("concurrent", func(t *testing.T) {
+		const allocations = 50
+
+		var (
+			mu      sync.Mutex
+			wg      sync.WaitGroup
+			numbers = map[order.Number]bool{}
+		)
+		for i := 0; i < allocations; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				n, err := allocator.Allocate(ctx, tenant, time.Now())
+				if err != nil {
+					t.Errorf("could not allocate number: %s", err)
+					return
+				}
+
+				mu.Lock()
+				numbers[n] = true
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		for seq := uint64(1); seq <= allocations; seq++ {
+			n, err := format.Format(time.Now(), seq)
+			if err != nil {
+				t.Fatalf("could not format number: %s", err)
+			}
+
+			if !numbers[n] {
+				t.Errorf("could not match allocated number: %s", n)
+			}
+		}
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		if _, err := allocator.Allocate(ctx, "unknown", time.Now()); !errors.Is(err, order.ErrNumberNotAllocated) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+}
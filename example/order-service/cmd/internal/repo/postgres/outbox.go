@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/relay"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+var (
+	_ relay.Outbox = &Outbox{}
+)
+
+const (
+	insertOutboxQuery = `INSERT INTO outbox (aggregate_id, name, payload, occurred_at) VALUES ($1, $2, $3, $4)`
+
+	pendingOutboxQuery = `SELECT id, name, payload, attempts, next_attempt_at FROM outbox
+WHERE dispatched_at IS NULL AND parked_at IS NULL
+AND id < COALESCE((
+	SELECT min(id) FROM outbox
+	WHERE dispatched_at IS NULL AND parked_at IS NULL AND next_attempt_at > now() AT TIME ZONE 'utc'
+), 9223372036854775807)
+ORDER BY id
+LIMIT $1`
+
+	dispatchOutboxQuery = `UPDATE outbox SET dispatched_at = now() AT TIME ZONE 'utc' WHERE id = $1`
+
+	failOutboxQuery = `UPDATE outbox SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3 WHERE id = $1`
+
+	parkOutboxQuery = `UPDATE outbox SET parked_at = now() AT TIME ZONE 'utc', last_error = $2 WHERE id = $1`
+)
+
+// Outbox represents the database table holding the events waiting to be published by the relay
+type Outbox struct {
+	db     *sql.DB
+	logger golog.Logger
+}
+
+// NewOutbox returns a database integration layer implementing relay.Outbox
+func NewOutbox(db *sql.DB, logger golog.Logger) *Outbox {
+	return &Outbox{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Pending queries the events not dispatched nor parked yet, in the order they were stored
+// It stops before the first message waiting for a retry, since the following ones must not be published before it
+// Messages whose payload cannot be decoded are parked and skipped, so that they do not block the following ones
+func (o *Outbox) Pending(ctx context.Context, limit int) ([]relay.Message, error) {
+	rows, err := o.db.QueryContext(ctx, pendingOutboxQuery, limit)
+	if err != nil {
+		o.logger.With(golog.Err(err)).Error(ctx, "outbox was not read from the database")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		msgs        []relay.Message
+		undecodable = map[int64]error{}
+	)
+	for rows.Next() {
+		var (
+			m       relay.Message
+			name    string
+			payload []byte
+		)
+		if err := rows.Scan(&m.ID, &name, &payload, &m.Attempts, &m.NextAttemptAt); err != nil {
+			o.logger.With(golog.Err(err)).Error(ctx, "outbox message was not scanned")
+			return nil, err
+		}
+
+		if m.Event, err = unmarshalEvent(name, payload); err != nil {
+			o.logger.With(golog.Err(err), golog.Int64("message_id", m.ID)).Error(ctx, "outbox message was not decoded")
+			undecodable[m.ID] = err
+			continue
+		}
+
+		msgs = append(msgs, m)
+	}
+	if err := rows.Err(); err != nil {
+		o.logger.With(golog.Err(err)).Error(ctx, "outbox was not read from the database")
+		return nil, err
+	}
+
+	// a message not parked is skipped anyway, and parked on the next poll
+	for id, reason := range undecodable {
+		_ = o.MarkParked(ctx, id, reason)
+	}
+
+	return msgs, nil
+}
+
+// MarkDispatched marks the message as published
+func (o *Outbox) MarkDispatched(ctx context.Context, id int64) error {
+	if _, err := o.db.ExecContext(ctx, dispatchOutboxQuery, id); err != nil {
+		o.logger.With(golog.Err(err), golog.Int64("message_id", id)).Error(ctx, "outbox message was not marked as dispatched")
+		return err
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed publishing attempt and schedules the next one
+func (o *Outbox) MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, reason error) error {
+	if _, err := o.db.ExecContext(ctx, failOutboxQuery, id, nextAttemptAt.UTC(), reason.Error()); err != nil {
+		o.logger.With(golog.Err(err), golog.Int64("message_id", id)).Error(ctx, "outbox message was not marked as failed")
+		return err
+	}
+
+	return nil
+}
+
+// MarkParked sets the message aside, so that it is never published again unless an operator clears parked_at
+func (o *Outbox) MarkParked(ctx context.Context, id int64, reason error) error {
+	if _, err := o.db.ExecContext(ctx, parkOutboxQuery, id, reason.Error()); err != nil {
+		o.logger.With(golog.Err(err), golog.Int64("message_id", id)).Error(ctx, "outbox message was not parked")
+		return err
+	}
+
+	o.logger.With(golog.Int64("message_id", id)).Warn(ctx, "outbox message was parked")
+	return nil
+}
+
+// addToOutbox stores the given events in the outbox using the given executor
+func addToOutbox(ctx context.Context, exec boil.ContextExecutor, events []order.Event) error {
+	for _, e := range events {
+		payload, err := marshalEvent(e)
+		if err != nil {
+			return fmt.Errorf("could not encode %s event: %w", e.EventName(), err)
+		}
+
+		if _, err := exec.ExecContext(ctx, insertOutboxQuery, e.AggregateID().String(), e.EventName(), payload, e.OccurredAt()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
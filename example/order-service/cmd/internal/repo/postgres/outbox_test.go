@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	gologTest "github.com/damianopetrungaro/golog/test"
+	"github.com/organization/order-service"
+)
+
+func TestPostgres_AddWithOutbox(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := NewWithOutbox(db, gologTest.NewNullLogger())
+
+	o := getPlacedOrder(t)
+	if err := o.MarkAsShipped(order.SystemClock{}); err != nil {
+		t.Fatalf("could not mark order as shipped: %s", err)
+	}
+
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %v", o)
+	}
+
+	found := getOrderByIDHelper(t, db, o.ID.String())
+	matchesOrder(t, o, found)
+
+	names := getOutboxEventNamesHelper(t, db, o.ID)
+	if len(names) != 2 || names[0] != "order.placed" || names[1] != "order.shipped" {
+		t.Errorf("could not match outbox events: %v", names)
+	}
+
+	// the events already in the outbox are not inserted again
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order again: %s", err)
+	}
+
+	if names := getOutboxEventNamesHelper(t, db, o.ID); len(names) != 2 {
+		t.Errorf("could not match outbox events once added again: %v", names)
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	clearOutboxHelper(t, db)
+
+	repo := NewWithOutbox(db, gologTest.NewNullLogger())
+	outbox := NewOutbox(db, gologTest.NewNullLogger())
+
+	o := getPlacedOrder(t)
+	if err := o.MarkAsShipped(order.SystemClock{}); err != nil {
+		t.Fatalf("could not mark order as shipped: %s", err)
+	}
+	want := o.Events()
+
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %v", o)
+	}
+
+	msgs, err := outbox.Pending(ctx, 10)
+	if err != nil {
+		t.Fatalf("could not read pending messages: %s", err)
+	}
+
+	if len(msgs) != len(want) {
+		t.Fatalf("could not match pending messages: %v", msgs)
+	}
+
+	for i, m := range msgs {
+		if m.Event.EventName() != want[i].EventName() || m.Event.AggregateID() != o.ID {
+			t.Errorf("could not match pending message: %#v", m.Event)
+		}
+	}
+
+	failedID := msgs[0].ID
+	if err := outbox.MarkFailed(ctx, msgs[0].ID, time.Now().Add(time.Minute), errors.New("broker unavailable")); err != nil {
+		t.Fatalf("could not mark message as failed: %s", err)
+	}
+
+	if err := outbox.MarkDispatched(ctx, msgs[1].ID); err != nil {
+		t.Fatalf("could not mark message as dispatched: %s", err)
+	}
+
+	// the failed message is not read until it is due
+	msgs, err = outbox.Pending(ctx, 10)
+	if err != nil {
+		t.Fatalf("could not read pending messages: %s", err)
+	}
+
+	if len(msgs) != 0 {
+		t.Fatalf("could not match pending messages waiting for a retry: %v", msgs)
+	}
+
+	if err := outbox.MarkFailed(ctx, failedID, time.Now().Add(-time.Second), errors.New("broker unavailable")); err != nil {
+		t.Fatalf("could not mark message as failed: %s", err)
+	}
+
+	msgs, err = outbox.Pending(ctx, 10)
+	if err != nil {
+		t.Fatalf("could not read pending messages: %s", err)
+	}
+
+	if len(msgs) != 1 {
+		t.Fatalf("could not match pending messages: %v", msgs)
+	}
+
+	if msgs[0].ID != failedID || msgs[0].Attempts != 2 || msgs[0].NextAttemptAt.After(time.Now()) {
+		t.Errorf("could not match failed message: %#v", msgs[0])
+	}
+}
+
+func TestOutbox_Pending_Waiting(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	clearOutboxHelper(t, db)
+
+	repo := NewWithOutbox(db, gologTest.NewNullLogger())
+	outbox := NewOutbox(db, gologTest.NewNullLogger())
+
+	for i := 0; i < 3; i++ {
+		if err := repo.Add(ctx, getPlacedOrder(t)); err != nil {
+			t.Fatalf("could not add order: %s", err)
+		}
+	}
+
+	msgs, err := outbox.Pending(ctx, 10)
+	if err != nil {
+		t.Fatalf("could not read pending messages: %s", err)
+	}
+
+	if len(msgs) != 3 {
+		t.Fatalf("could not match pending messages: %v", msgs)
+	}
+
+	// the next attempt is stored in UTC whatever the time zone of the relay
+	next := time.Now().Add(time.Minute).In(time.FixedZone("UTC+10", 10*60*60))
+	if err := outbox.MarkFailed(ctx, msgs[1].ID, next, errors.New("broker unavailable")); err != nil {
+		t.Fatalf("could not mark message as failed: %s", err)
+	}
+
+	found, err := outbox.Pending(ctx, 10)
+	if err != nil {
+		t.Fatalf("could not read pending messages: %s", err)
+	}
+
+	if len(found) != 1 || found[0].ID != msgs[0].ID {
+		t.Fatalf("could not match pending messages before the one waiting for a retry: %v", found)
+	}
+
+	var waiting time.Time
+	if err := db.QueryRowContext(ctx, `SELECT next_attempt_at FROM outbox WHERE id = $1`, msgs[1].ID).Scan(&waiting); err != nil {
+		t.Fatalf("could not read next attempt: %s", err)
+	}
+
+	if d := waiting.Sub(next.UTC()); d < -time.Millisecond || d > time.Millisecond {
+		t.Errorf("could not match next attempt stored in UTC: %s", waiting)
+	}
+}
+
+func TestOutbox_Pending_Undecodable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	clearOutboxHelper(t, db)
+
+	repo := NewWithOutbox(db, gologTest.NewNullLogger())
+	outbox := NewOutbox(db, gologTest.NewNullLogger())
+
+	poison := order.NewID()
+	if _, err := db.ExecContext(ctx, insertOutboxQuery, poison.String(), "order.unknown", []byte(`{}`), time.Now()); err != nil {
+		t.Fatalf("could not insert undecodable message: %s", err)
+	}
+
+	o := getPlacedOrder(t)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %v", o)
+	}
+
+	for i := 0; i < 2; i++ {
+		msgs, err := outbox.Pending(ctx, 10)
+		if err != nil {
+			t.Fatalf("could not read pending messages: %s", err)
+		}
+
+		if len(msgs) != 1 || msgs[0].Event.AggregateID() != o.ID {
+			t.Fatalf("could not match pending messages without the undecodable one: %v", msgs)
+		}
+	}
+
+	var lastError sql.NullString
+	row := db.QueryRowContext(ctx, `SELECT last_error FROM outbox WHERE aggregate_id = $1 AND parked_at IS NOT NULL`, poison.String())
+	if err := row.Scan(&lastError); err != nil {
+		t.Fatalf("could not find undecodable message parked: %s", err)
+	}
+
+	if lastError.String == "" {
+		t.Error("could not match last error of the parked message")
+	}
+}
+
+func getOutboxEventNamesHelper(t *testing.T, db *sql.DB, id order.ID) []string {
+	t.Helper()
+
+	rows, err := db.QueryContext(context.Background(), `SELECT name FROM outbox WHERE aggregate_id = $1 ORDER BY id`, id.String())
+	if err != nil {
+		t.Fatalf("could not query outbox: %s", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("could not scan outbox: %s", err)
+		}
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func clearOutboxHelper(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	if _, err := db.ExecContext(context.Background(), `DELETE FROM outbox`); err != nil {
+		t.Fatalf("could not clear outbox: %s", err)
+	}
+}
@@ -3,22 +3,34 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"github.com/damianopetrungaro/golog"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/organization/order-service"
 	"github.com/organization/order-service/cmd/internal/repo/postgres/internal"
+	"github.com/organization/order-service/cmd/internal/repo/transaction"
 	"github.com/volatiletech/null/v8"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"strings"
 )
 
 var (
 	_ order.Repo = &Postgres{}
 )
 
+// Unique constraints of the orders table
+const (
+	ordersPrimaryKey = "orders_pkey"
+	ordersNumberKey  = "orders_number_key"
+)
+
 // Postgres represents a database layer for the order.Repo
 type Postgres struct {
 	db     *sql.DB
+	outbox bool
 	logger golog.Logger
 }
 
@@ -30,49 +42,230 @@ func New(db *sql.DB, logger golog.Logger) *Postgres {
 	}
 }
 
-// Get queries an order from the database
+// NewWithOutbox returns a database integration layer implementing order.Repo
+// which stores the pending events of an order in the outbox table, within the same transaction of the order
+func NewWithOutbox(db *sql.DB, logger golog.Logger) *Postgres {
+	return &Postgres{
+		db:     db,
+		outbox: true,
+		logger: logger,
+	}
+}
+
+// Get queries an order and its items from the database, within the transaction carried by the context if any
 func (p *Postgres) Get(ctx context.Context, id order.ID) (*order.Order, error) {
+	exec := executor(ctx, p.db)
 	model, err := internal.Orders(
 		qm.Where("id=?", id.String()),
-	).One(ctx, p.db)
+	).One(ctx, exec)
 	if err != nil {
 		p.logger.With(golog.Err(err)).Error(ctx, "order was not read from the database")
 		return nil, order.ErrNotFound
 	}
 
-	return fromOrderModel(model), nil
+	return p.load(ctx, exec, model)
 }
 
-// Add inserts an order to the database
+// load maps the model to an order, reading its items using the given executor
+func (p *Postgres) load(ctx context.Context, exec boil.ContextExecutor, model *internal.Order) (*order.Order, error) {
+	o := fromOrderModel(model)
+	var err error
+	if o.ShippingAddress, err = fromAddressModel(model); err != nil {
+		p.logger.With(golog.Err(err)).Error(ctx, "order shipping address was not read from the database")
+		return nil, order.ErrNotFound
+	}
+
+	if o.Items, err = getItems(ctx, exec, o.ID); err != nil {
+		p.logger.With(golog.Err(err)).Error(ctx, "order items were not read from the database")
+		return nil, order.ErrNotFound
+	}
+
+	return o, nil
+}
+
+// Add inserts an order to the database, or updates it if its version matches the stored one
+// It runs within the transaction carried by the context if any, otherwise within its own
+// It increments the version of the order once stored, and marks its events as stored,
+// both being restored if the transaction carried by the context is rolled back
 func (p *Postgres) Add(ctx context.Context, o *order.Order) error {
-	if err := toOrderModel(o).Upsert(ctx, p.db, true, []string{"id"}, boil.Infer(), boil.Infer()); err != nil {
+	if tx, ok := txFrom(ctx); ok {
+		version, stored := o.Version, o.StoredEvents()
+		if err := p.add(ctx, tx, o); err != nil {
+			return err
+		}
+		o.MarkEventsStored()
+		// the order is left as it was before once the transaction is rolled back, so that adding it again stores it all
+		transaction.AfterRollback(ctx, func(context.Context) {
+			o.Version = version
+			o.UnmarkEventsStored(stored)
+		})
+		return nil
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		p.logger.With(golog.Err(err)).Error(ctx, "transaction was not started")
+		return order.ErrNotAdded
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	version := o.Version
+	if err := p.add(ctx, tx, o); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		p.logger.With(golog.Err(err)).Error(ctx, "transaction was not committed")
+		o.Version = version
+		return order.ErrNotAdded
+	}
+
+	o.MarkEventsStored()
+	return nil
+}
+
+// add stores the order, its items and its events not stored yet using the given transaction
+func (p *Postgres) add(ctx context.Context, tx *sql.Tx, o *order.Order) error {
+	model := toOrderModel(o)
+	model.Version = o.Version + 1
+	if err := save(ctx, tx, model, o.Version); err != nil {
+		if errors.Is(err, order.ErrConcurrentModification) {
+			p.logger.With(golog.Err(err)).Warn(ctx, "order was modified concurrently in the database")
+			return fmt.Errorf("%w: %w", order.ErrNotAdded, err)
+		}
+		if errors.Is(err, order.ErrDuplicateNumber) {
+			p.logger.With(golog.Err(err)).Warn(ctx, "order number was already taken in the database")
+			return fmt.Errorf("%w: %w", order.ErrNotAdded, err)
+		}
 		p.logger.With(golog.Err(err)).Error(ctx, "order was not inserted in the database")
 		return order.ErrNotAdded
 	}
 
+	if err := saveItems(ctx, tx, o.ID, o.Items); err != nil {
+		p.logger.With(golog.Err(err)).Error(ctx, "order items were not inserted in the database")
+		return order.ErrNotAdded
+	}
+
+	if p.outbox {
+		if err := addToOutbox(ctx, tx, o.UnstoredEvents()); err != nil {
+			p.logger.With(golog.Err(err)).Error(ctx, "order events were not inserted in the outbox")
+			return order.ErrNotAdded
+		}
+	}
+
+	o.Version = model.Version
+	return nil
+}
+
+// save inserts the model when it was never stored, otherwise updates it only if the stored version matches
+func save(ctx context.Context, exec boil.ContextExecutor, model *internal.Order, version int) error {
+	if version == 0 {
+		if err := model.Insert(ctx, exec, boil.Infer()); err != nil {
+			switch uniqueViolation(err) {
+			case ordersNumberKey:
+				return order.ErrDuplicateNumber
+			case ordersPrimaryKey:
+				return order.ErrConcurrentModification
+			}
+			return err
+		}
+
+		return nil
+	}
+
+	n, err := internal.Orders(
+		internal.OrderWhere.ID.EQ(model.ID),
+		internal.OrderWhere.Version.EQ(version),
+	).UpdateAll(ctx, exec, toOrderColumns(model))
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return order.ErrConcurrentModification
+	}
+
 	return nil
 }
 
+// uniqueViolation returns the name of the unique constraint violated by err, if any
+func uniqueViolation(err error) string {
+	const uniqueViolation = "23505"
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation {
+		return ""
+	}
+
+	return pqErr.Constraint
+}
+
 func fromOrderModel(model *internal.Order) *order.Order {
 	return &order.Order{
-		ID:          order.ID(uuid.MustParse(model.ID)),
-		Number:      order.Number([]byte(model.Number)),
-		Status:      order.Status(model.Status),
-		PlacedBy:    order.UserID(uuid.MustParse(model.PlacedBy)),
-		PlacedAt:    model.PlacedAt,
-		ShippedAt:   model.ShippedAt.Time,
-		DeliveredAt: model.DeliveredAt.Time,
+		ID:                 order.ID(uuid.MustParse(model.ID)),
+		Number:             order.Number(model.Number),
+		Status:             order.Status(model.Status),
+		PlacedBy:           order.UserID(uuid.MustParse(model.PlacedBy)),
+		PlacedAt:           model.PlacedAt,
+		ShippedAt:          model.ShippedAt.Time,
+		DeliveredAt:        model.DeliveredAt.Time,
+		CancelledAt:        model.CancelledAt.Time,
+		CancellationReason: order.CancellationReason(model.CancellationReason.String),
+		Version:            model.Version,
 	}
 }
 
 func toOrderModel(o *order.Order) *internal.Order {
 	return &internal.Order{
-		ID:          o.ID.String(),
-		Number:      o.Number.String(),
-		Status:      o.Status.String(),
-		PlacedBy:    o.PlacedBy.String(),
-		PlacedAt:    o.PlacedAt,
-		ShippedAt:   null.TimeFrom(o.ShippedAt),
-		DeliveredAt: null.TimeFrom(o.DeliveredAt),
+		ID:                 o.ID.String(),
+		Number:             o.Number.String(),
+		Status:             o.Status.String(),
+		PlacedBy:           o.PlacedBy.String(),
+		PlacedAt:           o.PlacedAt,
+		ShippedAt:          null.TimeFrom(o.ShippedAt),
+		DeliveredAt:        null.TimeFrom(o.DeliveredAt),
+		CancelledAt:        null.NewTime(o.CancelledAt, !o.CancelledAt.IsZero()),
+		CancellationReason: null.NewString(o.CancellationReason.String(), !o.CancellationReason.IsZero()),
+		Version:            o.Version,
+		ShippingRecipient:  null.NewString(o.ShippingAddress.Recipient(), !o.ShippingAddress.IsZero()),
+		ShippingLines:      null.NewString(strings.Join(o.ShippingAddress.Lines(), "\n"), !o.ShippingAddress.IsZero()),
+		ShippingCity:       null.NewString(o.ShippingAddress.City(), !o.ShippingAddress.IsZero()),
+		ShippingPostalCode: null.NewString(o.ShippingAddress.PostalCode(), !o.ShippingAddress.IsZero()),
+		ShippingCountry:    null.NewString(o.ShippingAddress.Country(), !o.ShippingAddress.IsZero()),
+	}
+}
+
+// fromAddressModel returns the shipping address stored in the model, the zero Address when none was stored
+func fromAddressModel(model *internal.Order) (order.Address, error) {
+	if !model.ShippingCountry.Valid {
+		return order.Address{}, nil
+	}
+
+	return order.ParseAddress(
+		model.ShippingRecipient.String,
+		strings.Split(model.ShippingLines.String, "\n"),
+		model.ShippingCity.String,
+		model.ShippingPostalCode.String,
+		model.ShippingCountry.String,
+	)
+}
+
+func toOrderColumns(model *internal.Order) internal.M {
+	return internal.M{
+		internal.OrderColumns.Number:             model.Number,
+		internal.OrderColumns.Status:             model.Status,
+		internal.OrderColumns.PlacedBy:           model.PlacedBy,
+		internal.OrderColumns.PlacedAt:           model.PlacedAt,
+		internal.OrderColumns.ShippedAt:          model.ShippedAt,
+		internal.OrderColumns.DeliveredAt:        model.DeliveredAt,
+		internal.OrderColumns.CancelledAt:        model.CancelledAt,
+		internal.OrderColumns.CancellationReason: model.CancellationReason,
+		internal.OrderColumns.Version:            model.Version,
+		internal.OrderColumns.ShippingRecipient:  model.ShippingRecipient,
+		internal.OrderColumns.ShippingLines:      model.ShippingLines,
+		internal.OrderColumns.ShippingCity:       model.ShippingCity,
+		internal.OrderColumns.ShippingPostalCode: model.ShippingPostalCode,
+		internal.OrderColumns.ShippingCountry:    model.ShippingCountry,
 	}
 }
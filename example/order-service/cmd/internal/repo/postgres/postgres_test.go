@@ -3,12 +3,16 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	gologTest "github.com/damianopetrungaro/golog/test"
 	"github.com/google/uuid"
 	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/instrument"
 	"github.com/organization/order-service/cmd/internal/repo/postgres/internal"
+	"github.com/organization/order-service/cmd/internal/repo/repotest"
 	"github.com/volatiletech/sqlboiler/v4/boil"
 	"github.com/volatiletech/sqlboiler/v4/queries/qm"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -50,6 +54,105 @@ func TestOrderRepo_Get(t *testing.T) {
 	matchesOrder(t, o, found)
 }
 
+func TestPostgres_Add_ConcurrentModification(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getPostgres(t, db)
+
+	o := getPlacedOrder(t)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %v", o)
+	}
+
+	if o.Version != 1 {
+		t.Fatalf("could not match version once added: %d", o.Version)
+	}
+
+	first, err := repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+
+	second, err := repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+
+	if err := first.MarkAsShipped(order.SystemClock{}); err != nil {
+		t.Fatalf("could not mark order as shipped: %s", err)
+	}
+
+	if err := repo.Add(ctx, first); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	if first.Version != 2 {
+		t.Fatalf("could not match version once updated: %d", first.Version)
+	}
+
+	if err := second.MarkAsShipped(order.SystemClock{}); err != nil {
+		t.Fatalf("could not mark order as shipped: %s", err)
+	}
+
+	if err := repo.Add(ctx, second); !errors.Is(err, order.ErrConcurrentModification) {
+		t.Fatalf("could not match error: %s", err)
+	}
+
+	if err := repo.Add(ctx, getPlacedOrder(t)); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	duplicated := *o
+	duplicated.Version = 0
+	if err := repo.Add(ctx, &duplicated); !errors.Is(err, order.ErrConcurrentModification) {
+		t.Fatalf("could not match error: %s", err)
+	}
+}
+
+func TestPostgres_Add_DuplicateNumber(t *testing.T) {
+	ctx := context.Background()
+	db := getDB(t)
+	repo := getPostgres(t, db)
+
+	o := getPlacedOrder(t)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	duplicated, err := order.Place(order.SystemClock{}, order.NewID(), o.Number, order.UserID(uuid.New()), getRandomItems(t))
+	if err != nil {
+		t.Fatalf("could not place order: %s", err)
+	}
+
+	if err := repo.Add(ctx, duplicated); !errors.Is(err, order.ErrDuplicateNumber) {
+		t.Fatalf("could not match error: %s", err)
+	}
+}
+
+func TestPostgres_Contract(t *testing.T) {
+	t.Run("postgres", func(t *testing.T) {
+		repotest.Run(t, func(t *testing.T) order.Repo {
+			return getPostgres(t, getDB(t))
+		})
+	})
+
+	t.Run("with outbox", func(t *testing.T) {
+		repotest.Run(t, func(t *testing.T) order.Repo {
+			return NewWithOutbox(getDB(t), gologTest.NewNullLogger())
+		})
+	})
+
+	t.Run("instrumented", func(t *testing.T) {
+		repotest.Run(t, func(t *testing.T) order.Repo {
+			return instrument.New(getPostgres(t, getDB(t)), "postgres")
+		})
+	})
+}
+
 func getPostgres(t *testing.T, db *sql.DB) *Postgres {
 	t.Helper()
 
@@ -57,9 +160,14 @@ func getPostgres(t *testing.T, db *sql.DB) *Postgres {
 }
 
 func addOrderHelper(t *testing.T, db *sql.DB, b *order.Order) {
+	b.Version++
 	if err := toOrderModel(b).Insert(context.Background(), db, boil.Infer()); err != nil {
 		t.Fatalf("could not execute insert query: %s", err)
 	}
+
+	if err := saveItems(context.Background(), db, b.ID, b.Items); err != nil {
+		t.Fatalf("could not execute insert items query: %s", err)
+	}
 }
 
 func getOrderByIDHelper(t *testing.T, db *sql.DB, id string) *order.Order {
@@ -69,21 +177,63 @@ func getOrderByIDHelper(t *testing.T, db *sql.DB, id string) *order.Order {
 		t.Fatalf("could not query order")
 	}
 
-	return fromOrderModel(model)
+	o := fromOrderModel(model)
+	if o.ShippingAddress, err = fromAddressModel(model); err != nil {
+		t.Fatalf("could not parse order shipping address: %s", err)
+	}
+
+	if o.Items, err = getItems(context.Background(), db, o.ID); err != nil {
+		t.Fatalf("could not query order items")
+	}
+
+	return o
 }
 
 func getRandomOrder(t *testing.T) *order.Order {
 	t.Helper()
 
 	return &order.Order{
-		ID:          order.ID(uuid.New()),
-		Number:      order.GenerateNumber(),
-		Status:      order.Shipped,
-		PlacedBy:    order.UserID(uuid.New()),
-		PlacedAt:    time.Now(),
-		ShippedAt:   time.Now(),
-		DeliveredAt: time.Time{},
+		ID:              order.ID(uuid.New()),
+		Number:          order.GenerateNumber(),
+		Status:          order.Shipped,
+		PlacedBy:        order.UserID(uuid.New()),
+		Items:           getRandomItems(t),
+		ShippingAddress: getRandomAddress(t),
+		PlacedAt:        time.Now(),
+		ShippedAt:       time.Now(),
+		DeliveredAt:     time.Time{},
+	}
+}
+
+func getRandomAddress(t *testing.T) order.Address {
+	t.Helper()
+
+	a, err := order.ParseAddress("Recipient "+uuid.NewString()[:8], []string{"Invalidenstraße 116", "Floor 2"}, "Berlin", "10115", "DE")
+	if err != nil {
+		t.Fatalf("could not parse address: %s", err)
+	}
+
+	return a
+}
+
+func getRandomItems(t *testing.T) []order.LineItem {
+	t.Helper()
+
+	return []order.LineItem{
+		{SKU: order.SKU("SKU-" + uuid.NewString()[:8]), Quantity: 2, UnitPrice: order.NewMoney(1050, order.EUR)},
+		{SKU: order.SKU("SKU-" + uuid.NewString()[:8]), Quantity: 1, UnitPrice: order.NewMoney(399, order.EUR)},
+	}
+}
+
+func getPlacedOrder(t *testing.T) *order.Order {
+	t.Helper()
+
+	o, err := order.Place(order.SystemClock{}, order.NewID(), order.GenerateNumber(), order.UserID(uuid.New()), getRandomItems(t))
+	if err != nil {
+		t.Fatalf("could not place order: %s", err)
 	}
+
+	return o
 }
 
 func matchesOrder(t *testing.T, a, b *order.Order) {
@@ -109,6 +259,9 @@ func matchesOrder(t *testing.T, a, b *order.Order) {
 	if a.PlacedBy != b.PlacedBy {
 		t.Fail()
 	}
+	if !reflect.DeepEqual(a.Items, b.Items) {
+		t.Fail()
+	}
 	if a.PlacedAt.Compare(a.PlacedAt) != 0 {
 		t.Fail()
 	}
@@ -118,4 +271,16 @@ func matchesOrder(t *testing.T, a, b *order.Order) {
 	if a.DeliveredAt.Compare(a.DeliveredAt) != 0 {
 		t.Fail()
 	}
+	if a.CancelledAt.Compare(a.CancelledAt) != 0 {
+		t.Fail()
+	}
+	if a.CancellationReason != b.CancellationReason {
+		t.Fail()
+	}
+	if !a.ShippingAddress.Equal(b.ShippingAddress) {
+		t.Fail()
+	}
+	if a.Version != b.Version {
+		t.Fail()
+	}
 }
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/organization/order-service/cmd/internal/repo/transaction"
+	service "github.com/organization/order-service/internal"
+	"github.com/volatiletech/sqlboiler/v4/boil"
+)
+
+var (
+	_ service.Transactor = &Transactor{}
+)
+
+type txKey struct{}
+
+// Transactor represents a unit of work running in a database transaction
+// The Postgres repositories pick the transaction up from the context, falling back to the database outside of it
+type Transactor struct {
+	db     *sql.DB
+	logger golog.Logger
+}
+
+// NewTransactor returns a database integration layer implementing internal.Transactor
+func NewTransactor(db *sql.DB, logger golog.Logger) *Transactor {
+	return &Transactor{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithinTransaction calls fn with a context carrying a new transaction, committed when fn succeeds
+// The callbacks registered with transaction.AfterCommit run once the transaction is committed,
+// the ones registered with transaction.AfterRollback once it is rolled back or fails to commit
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := txFrom(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		t.logger.With(golog.Err(err)).Error(ctx, "transaction was not started")
+		return fmt.Errorf("%w: %w", service.ErrNotCommitted, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	txCtx, hooks := transaction.WithHooks(context.WithValue(ctx, txKey{}, tx))
+	if err := fn(txCtx); err != nil {
+		_ = tx.Rollback()
+		hooks.Rollback(ctx)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		t.logger.With(golog.Err(err)).Error(ctx, "transaction was not committed")
+		hooks.Rollback(ctx)
+		return fmt.Errorf("%w: %w", service.ErrNotCommitted, err)
+	}
+
+	hooks.Run(ctx)
+
+	return nil
+}
+
+// txFrom returns the transaction carried by the context, if any
+func txFrom(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok
+}
+
+// executor returns the transaction carried by the context, or the database outside of a transaction
+func executor(ctx context.Context, db *sql.DB) boil.ContextExecutor {
+	if tx, ok := txFrom(ctx); ok {
+		return tx
+	}
+
+	return db
+}
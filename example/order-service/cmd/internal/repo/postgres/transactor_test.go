@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gocache "github.com/damianopetrungaro/go-cache"
+	gologTest "github.com/damianopetrungaro/golog/test"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/cache"
+	"github.com/organization/order-service/cmd/internal/repo/transaction"
+	service "github.com/organization/order-service/internal"
+)
+
+func TestTransactor_WithinTransaction(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	db := getDB(t)
+	repo := getPostgres(t, db)
+	transactor := NewTransactor(db, gologTest.NewNullLogger())
+
+	t.Run("committed", func(t *testing.T) {
+		o := getRandomOrder(t)
+
+		var committed bool
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := repo.Add(ctx, o); err != nil {
+				return err
+			}
+
+			found, err := repo.Get(ctx, o.ID)
+			if err != nil {
+				t.Fatalf("could not get order within the transaction: %s", err)
+			}
+			matchesOrder(t, o, found)
+
+			if _, err := repo.Get(context.Background(), o.ID); !errors.Is(err, order.ErrNotFound) {
+				t.Fatalf("could get order outside of the transaction: %s", err)
+			}
+
+			transaction.AfterCommit(ctx, func(context.Context) {
+				committed = true
+			})
+			if committed {
+				t.Fatal("could not match callback deferred until commit")
+			}
+
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("could not run transaction: %s", err)
+		}
+
+		if !committed {
+			t.Fatal("could not match callback run once committed")
+		}
+
+		found := getOrderByIDHelper(t, db, o.ID.String())
+		matchesOrder(t, o, found)
+	})
+
+	t.Run("rolled back", func(t *testing.T) {
+		o := getRandomOrder(t)
+		errRollback := errors.New("rollback")
+
+		var committed, rolledBack bool
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := repo.Add(ctx, o); err != nil {
+				return err
+			}
+
+			transaction.AfterCommit(ctx, func(context.Context) {
+				committed = true
+			})
+			transaction.AfterRollback(ctx, func(context.Context) {
+				rolledBack = true
+			})
+
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		if committed {
+			t.Fatal("could not match callback dropped once rolled back")
+		}
+
+		if !rolledBack {
+			t.Fatal("could not match callback run once rolled back")
+		}
+
+		if _, err := repo.Get(ctx, o.ID); !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("could get order rolled back: %s", err)
+		}
+
+		if o.Version != 0 {
+			t.Fatalf("could not match version once rolled back: %d", o.Version)
+		}
+
+		// the order is left as it was before, so that it can be added again
+		if err := repo.Add(ctx, o); err != nil {
+			t.Fatalf("could not add order once rolled back: %s", err)
+		}
+	})
+
+	t.Run("commit failed", func(t *testing.T) {
+		logger := gologTest.NewNullLogger()
+		store := cache.NewStore(cache.DefaultConfig())
+		t.Cleanup(func() {
+			_ = store.Close()
+		})
+		cachedRepo := cache.New(repo, store, logger)
+
+		o := getRandomOrder(t)
+		addOrderHelper(t, db, o)
+		if _, err := cachedRepo.Get(ctx, o.ID); err != nil {
+			t.Fatalf("could not get order: %s", err)
+		}
+
+		// the transaction is rolled back once its context is cancelled, so that committing it fails
+		txCtx, txCancel := context.WithCancel(ctx)
+		t.Cleanup(func() {
+			txCancel()
+		})
+		err := transactor.WithinTransaction(txCtx, func(ctx context.Context) error {
+			o.Status, o.DeliveredAt = order.Delivered, time.Now()
+			if err := cachedRepo.Add(ctx, o); err != nil {
+				return err
+			}
+
+			txCancel()
+			return nil
+		})
+		if !errors.Is(err, service.ErrNotCommitted) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		if _, err := store.Get(ctx, o.ID); !errors.Is(err, gocache.ErrNotFound) {
+			t.Fatalf("could find order in the cache once the commit failed: %s", err)
+		}
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		o := getRandomOrder(t)
+		errRollback := errors.New("rollback")
+
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				return repo.Add(ctx, o)
+			}); err != nil {
+				return err
+			}
+
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		if _, err := repo.Get(ctx, o.ID); !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("could get order rolled back by the outer transaction: %s", err)
+		}
+	})
+}
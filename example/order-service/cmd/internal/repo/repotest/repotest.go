@@ -0,0 +1,407 @@
+// Package repotest provides the conformance suite every order.Repo implementation of the project runs against
+package repotest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/organization/order-service"
+)
+
+// concurrency is the number of goroutines racing against each other in the concurrency tests
+const concurrency = 8
+
+// Factory returns the order.Repo under test
+// Repositories may be shared by the tests, which only rely on the orders they add themselves
+type Factory func(t *testing.T) order.Repo
+
+// Run runs the conformance suite against the order.Repo returned by the factory
+// Orders are built through the domain commands only, so that event sourced repositories can store them too
+func Run(t *testing.T, factory Factory) {
+	t.Helper()
+
+	t.Run("get not found", func(t *testing.T) {
+		testGetNotFound(t, factory(t))
+	})
+	t.Run("round trip", func(t *testing.T) {
+		testRoundTrip(t, factory(t))
+	})
+	t.Run("overwrite", func(t *testing.T) {
+		testOverwrite(t, factory(t))
+	})
+	t.Run("isolation", func(t *testing.T) {
+		testIsolation(t, factory(t))
+	})
+	t.Run("not added", func(t *testing.T) {
+		testNotAdded(t, factory(t))
+	})
+	t.Run("concurrent writers", func(t *testing.T) {
+		testConcurrentWriters(t, factory(t))
+	})
+	t.Run("concurrent orders", func(t *testing.T) {
+		testConcurrentOrders(t, factory(t))
+	})
+}
+
+func testGetNotFound(t *testing.T, repo order.Repo) {
+	o, err := repo.Get(context.Background(), order.NewID())
+	if !errors.Is(err, order.ErrNotFound) {
+		t.Fatalf("could not match error: %s", err)
+	}
+
+	if o != nil {
+		t.Fatalf("could not match a nil order: %v", o)
+	}
+}
+
+func testRoundTrip(t *testing.T, repo order.Repo) {
+	tests := map[string]func(t *testing.T, clock *order.FakeClock, o *order.Order){
+		"placed": func(t *testing.T, clock *order.FakeClock, o *order.Order) {},
+		"delivered": func(t *testing.T, clock *order.FakeClock, o *order.Order) {
+			clock.Advance(time.Hour)
+			mustDo(t, o.ChangeShippingAddress(clock, newAddress(t)))
+			clock.Advance(time.Hour)
+			mustDo(t, o.MarkAsShipped(clock))
+			clock.Advance(24 * time.Hour)
+			mustDo(t, o.MarkAsDelivered(clock))
+		},
+		"cancelled": func(t *testing.T, clock *order.FakeClock, o *order.Order) {
+			clock.Advance(time.Minute)
+			mustDo(t, o.Cancel(clock, order.PaymentFailed))
+		},
+	}
+
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			clock := newClock(t)
+			o := newPlacedOrder(t, clock)
+			change(t, clock, o)
+
+			if err := repo.Add(ctx, o); err != nil {
+				t.Fatalf("could not add order: %s", err)
+			}
+
+			if o.Version <= 0 {
+				t.Fatalf("could not match version incremented once added: %d", o.Version)
+			}
+
+			found, err := repo.Get(ctx, o.ID)
+			if err != nil {
+				t.Fatalf("could not get order: %s", err)
+			}
+
+			matchesOrder(t, o, found)
+		})
+	}
+}
+
+func testOverwrite(t *testing.T, repo order.Repo) {
+	ctx := context.Background()
+	clock := newClock(t)
+	o := newPlacedOrder(t, clock)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	found, err := repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+	found.PullEvents()
+
+	clock.Advance(time.Hour)
+	mustDo(t, found.MarkAsShipped(clock))
+	if err := repo.Add(ctx, found); err != nil {
+		t.Fatalf("could not add order once shipped: %s", err)
+	}
+
+	if found.Version <= o.Version {
+		t.Fatalf("could not match version incremented once overwritten: got %d, was %d", found.Version, o.Version)
+	}
+
+	shipped, err := repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+
+	matchesOrder(t, found, shipped)
+}
+
+func testIsolation(t *testing.T, repo order.Repo) {
+	ctx := context.Background()
+	clock := newClock(t)
+	o := newPlacedOrder(t, clock)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+	want := *o
+	want.Items = append([]order.LineItem(nil), o.Items...)
+
+	// neither the added nor the returned orders are stored as they are, so that changing them without Add has no effect
+	mustDo(t, o.Cancel(clock, order.CustomerRequest))
+	o.Items[0].Quantity++
+
+	found, err := repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+	mustDo(t, found.MarkAsShipped(clock))
+	found.Items[0].Quantity++
+
+	found, err = repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+
+	matchesOrder(t, &want, found)
+}
+
+func testNotAdded(t *testing.T, repo order.Repo) {
+	ctx := context.Background()
+	clock := newClock(t)
+	o := newPlacedOrder(t, clock)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	t.Run("stale version", func(t *testing.T) {
+		first, err := repo.Get(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("could not get order: %s", err)
+		}
+		first.PullEvents()
+
+		second, err := repo.Get(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("could not get order: %s", err)
+		}
+		second.PullEvents()
+
+		mustDo(t, first.MarkAsShipped(clock))
+		if err := repo.Add(ctx, first); err != nil {
+			t.Fatalf("could not add order: %s", err)
+		}
+
+		mustDo(t, second.Cancel(clock, order.OutOfStock))
+		err = repo.Add(ctx, second)
+		if !errors.Is(err, order.ErrNotAdded) || !errors.Is(err, order.ErrConcurrentModification) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		found, err := repo.Get(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("could not get order: %s", err)
+		}
+
+		matchesOrder(t, first, found)
+	})
+
+	t.Run("duplicate id", func(t *testing.T) {
+		duplicated, err := order.Place(clock, o.ID, order.GenerateNumber(), newUserID(t), newItems(t))
+		if err != nil {
+			t.Fatalf("could not place order: %s", err)
+		}
+
+		err = repo.Add(ctx, duplicated)
+		if !errors.Is(err, order.ErrNotAdded) || !errors.Is(err, order.ErrConcurrentModification) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+
+	t.Run("duplicate number", func(t *testing.T) {
+		duplicated, err := order.Place(clock, order.NewID(), o.Number, newUserID(t), newItems(t))
+		if err != nil {
+			t.Fatalf("could not place order: %s", err)
+		}
+
+		err = repo.Add(ctx, duplicated)
+		if !errors.Is(err, order.ErrNotAdded) || !errors.Is(err, order.ErrDuplicateNumber) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		if _, err := repo.Get(ctx, duplicated.ID); !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+}
+
+func testConcurrentWriters(t *testing.T, repo order.Repo) {
+	ctx := context.Background()
+	clock := newClock(t)
+	o := newPlacedOrder(t, clock)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	orders := make([]*order.Order, concurrency)
+	for i := range orders {
+		found, err := repo.Get(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("could not get order: %s", err)
+		}
+		found.PullEvents()
+		mustDo(t, found.MarkAsShipped(clock))
+		orders[i] = found
+	}
+
+	errs := make([]error, concurrency)
+	var wg sync.WaitGroup
+	for i := range orders {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.Add(ctx, orders[i])
+		}(i)
+	}
+	wg.Wait()
+
+	var added int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			added++
+		case !errors.Is(err, order.ErrConcurrentModification):
+			t.Errorf("could not match error: %s", err)
+		}
+	}
+
+	if added != 1 {
+		t.Fatalf("could not match a single writer winning: %d", added)
+	}
+}
+
+func testConcurrentOrders(t *testing.T, repo order.Repo) {
+	ctx := context.Background()
+	clock := newClock(t)
+
+	orders := make([]*order.Order, concurrency)
+	for i := range orders {
+		orders[i] = newPlacedOrder(t, clock)
+	}
+
+	errs := make([]error, concurrency)
+	var wg sync.WaitGroup
+	for i := range orders {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = repo.Add(ctx, orders[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for i, o := range orders {
+		if errs[i] != nil {
+			t.Fatalf("could not add order: %s", errs[i])
+		}
+
+		found, err := repo.Get(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("could not get order: %s", err)
+		}
+
+		matchesOrder(t, o, found)
+	}
+}
+
+func matchesOrder(t *testing.T, want, got *order.Order) {
+	t.Helper()
+
+	if got.ID != want.ID {
+		t.Errorf("could not match id: got %s, want %s", got.ID, want.ID)
+	}
+	if got.Number != want.Number {
+		t.Errorf("could not match number: got %s, want %s", got.Number, want.Number)
+	}
+	if got.Status != want.Status {
+		t.Errorf("could not match status: got %s, want %s", got.Status, want.Status)
+	}
+	if got.PlacedBy != want.PlacedBy {
+		t.Errorf("could not match placed by: got %s, want %s", got.PlacedBy, want.PlacedBy)
+	}
+	if !reflect.DeepEqual(got.Items, want.Items) {
+		t.Errorf("could not match items: got %v, want %v", got.Items, want.Items)
+	}
+	if !got.ShippingAddress.Equal(want.ShippingAddress) {
+		t.Errorf("could not match shipping address: got %s, want %s", got.ShippingAddress, want.ShippingAddress)
+	}
+	if !got.PlacedAt.Equal(want.PlacedAt) {
+		t.Errorf("could not match placed at: got %s, want %s", got.PlacedAt, want.PlacedAt)
+	}
+	if !got.ShippedAt.Equal(want.ShippedAt) {
+		t.Errorf("could not match shipped at: got %s, want %s", got.ShippedAt, want.ShippedAt)
+	}
+	if !got.DeliveredAt.Equal(want.DeliveredAt) {
+		t.Errorf("could not match delivered at: got %s, want %s", got.DeliveredAt, want.DeliveredAt)
+	}
+	if !got.CancelledAt.Equal(want.CancelledAt) {
+		t.Errorf("could not match cancelled at: got %s, want %s", got.CancelledAt, want.CancelledAt)
+	}
+	if got.CancellationReason != want.CancellationReason {
+		t.Errorf("could not match cancellation reason: got %s, want %s", got.CancellationReason, want.CancellationReason)
+	}
+	if got.Version != want.Version {
+		t.Errorf("could not match version: got %d, want %d", got.Version, want.Version)
+	}
+	if events := got.Events(); len(events) != 0 {
+		t.Errorf("could not match order read without pending events: %v", events)
+	}
+}
+
+func mustDo(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("could not change order: %s", err)
+	}
+}
+
+// newClock returns a clock at a whole second, so that times survive the precision of every storage
+func newClock(t *testing.T) *order.FakeClock {
+	t.Helper()
+
+	return order.NewFakeClock(time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC))
+}
+
+func newPlacedOrder(t *testing.T, clock order.Clock) *order.Order {
+	t.Helper()
+
+	o, err := order.Place(clock, order.NewID(), order.GenerateNumber(), newUserID(t), newItems(t))
+	if err != nil {
+		t.Fatalf("could not place order: %s", err)
+	}
+
+	return o
+}
+
+func newUserID(t *testing.T) order.UserID {
+	t.Helper()
+
+	return order.UserID(uuid.New())
+}
+
+func newItems(t *testing.T) []order.LineItem {
+	t.Helper()
+
+	return []order.LineItem{
+		{SKU: order.SKU("SKU-" + uuid.NewString()[:8]), Quantity: 2, UnitPrice: order.NewMoney(1050, order.EUR)},
+		{SKU: order.SKU("SKU-" + uuid.NewString()[:8]), Quantity: 1, UnitPrice: order.NewMoney(399, order.EUR)},
+	}
+}
+
+func newAddress(t *testing.T) order.Address {
+	t.Helper()
+
+	a, err := order.ParseAddress("Jane Doe", []string{"Invalidenstraße 116", "Floor 2"}, "Berlin", "10115", "DE")
+	if err != nil {
+		t.Fatalf("could not parse address: %s", err)
+	}
+
+	return a
+}
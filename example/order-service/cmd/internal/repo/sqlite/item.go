@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/organization/order-service"
+)
+
+const (
+	deleteItemsQuery = `DELETE FROM order_items WHERE order_id = ?`
+
+	insertItemQuery = `INSERT INTO order_items (order_id, position, sku, quantity, unit_price_amount, unit_price_currency) VALUES (?, ?, ?, ?, ?, ?)`
+
+	selectItemsQuery = `SELECT order_id, sku, quantity, unit_price_amount, unit_price_currency FROM order_items`
+)
+
+// executor represents what the items are read and written with, either the database or a transaction
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// saveItems replaces the items of an order using the given executor
+func saveItems(ctx context.Context, exec executor, id order.ID, items []order.LineItem) error {
+	if _, err := exec.ExecContext(ctx, deleteItemsQuery, id.String()); err != nil {
+		return err
+	}
+
+	for pos, i := range items {
+		if _, err := exec.ExecContext(ctx, insertItemQuery, id.String(), pos, i.SKU.String(), i.Quantity, i.UnitPrice.Amount(), i.UnitPrice.Currency()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getItems queries the items of an order using the given executor
+func getItems(ctx context.Context, exec executor, id order.ID) ([]order.LineItem, error) {
+	items, err := getItemsOf(ctx, exec, []order.ID{id})
+	if err != nil {
+		return nil, err
+	}
+
+	return items[id], nil
+}
+
+// getItemsOf queries the items of many orders at once using the given executor, grouped by order
+func getItemsOf(ctx context.Context, exec executor, ids []order.ID) (map[order.ID][]order.LineItem, error) {
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id.String()
+	}
+
+	query := selectItemsQuery + ` WHERE order_id IN (?` + strings.Repeat(`, ?`, len(ids)-1) + `) ORDER BY order_id, position`
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := map[order.ID][]order.LineItem{}
+	for rows.Next() {
+		var (
+			i        order.LineItem
+			id       string
+			sku      string
+			amount   int64
+			currency order.Currency
+		)
+		if err := rows.Scan(&id, &sku, &i.Quantity, &amount, &currency); err != nil {
+			return nil, err
+		}
+
+		oID, err := order.ParseID(id)
+		if err != nil {
+			return nil, err
+		}
+
+		i.SKU = order.SKU(sku)
+		i.UnitPrice = order.NewMoney(amount, currency)
+		items[oID] = append(items[oID], i)
+	}
+
+	return items, rows.Err()
+}
@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/organization/order-service"
+)
+
+var (
+	_ order.Lister = &SQLite{}
+)
+
+// List queries a page of the orders matching the filter, from the most recently placed
+// Pages are read by keyset from the cursor, so that their cost does not grow with the number of orders before them
+func (s *SQLite) List(ctx context.Context, f order.Filter, c order.Cursor) (order.Page, error) {
+	if err := f.Validate(); err != nil {
+		return order.Page{}, fmt.Errorf("%w: %w", order.ErrNotListed, err)
+	}
+
+	where, args := filterWhere(f)
+	cursor, cursorArgs, orderBy := cursorWhere(c)
+	if cursor != "" {
+		where = append(where, cursor)
+		args = append(args, cursorArgs...)
+	}
+
+	query := selectOrderQuery
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	query += ` ORDER BY ` + orderBy + ` LIMIT ?`
+	args = append(args, f.Size()+1)
+
+	var orders []*order.Order
+	err := s.read(ctx, func(tx *sql.Tx) error {
+		var err error
+		orders, err = queryOrders(ctx, tx, query, args...)
+		return err
+	})
+	if err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "orders were not read from the database")
+		return order.Page{}, order.ErrNotListed
+	}
+
+	return order.NewPage(orders, f.Size(), c), nil
+}
+
+// queryOrders queries the orders selected by the query using the given executor, loading the items of all of them at once
+func queryOrders(ctx context.Context, exec executor, query string, args ...any) ([]*order.Order, error) {
+	rows, err := exec.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		orders []*order.Order
+		ids    []order.ID
+	)
+	for rows.Next() {
+		o, err := scanOrder(rows)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, o)
+		ids = append(ids, o.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(orders) == 0 {
+		return orders, nil
+	}
+
+	items, err := getItemsOf(ctx, exec, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, o := range orders {
+		o.Items = items[o.ID]
+	}
+
+	return orders, nil
+}
+
+// cursorWhere returns the condition selecting the orders following the cursor in its direction and how to sort them
+func cursorWhere(c order.Cursor) (string, []any, string) {
+	if c.Direction == order.Backward {
+		if c.IsZero() {
+			return "", nil, `placed_at ASC, id ASC`
+		}
+		return `(placed_at, id) > (?, ?)`, []any{formatTime(c.PlacedAt), c.ID.String()}, `placed_at ASC, id ASC`
+	}
+
+	if c.IsZero() {
+		return "", nil, `placed_at DESC, id DESC`
+	}
+	return `(placed_at, id) < (?, ?)`, []any{formatTime(c.PlacedAt), c.ID.String()}, `placed_at DESC, id DESC`
+}
+
+// filterWhere returns the conditions selecting the orders matching the filter
+func filterWhere(f order.Filter) ([]string, []any) {
+	var (
+		where []string
+		args  []any
+	)
+	if !f.PlacedBy.IsZero() {
+		where = append(where, `placed_by = ?`)
+		args = append(args, f.PlacedBy.String())
+	}
+
+	if len(f.Statuses) > 0 {
+		where = append(where, `status IN (?`+strings.Repeat(`, ?`, len(f.Statuses)-1)+`)`)
+		for _, s := range f.Statuses {
+			args = append(args, s.String())
+		}
+	}
+
+	for column, r := range map[string]order.TimeRange{"placed_at": f.PlacedAt, "shipped_at": f.ShippedAt, "delivered_at": f.DeliveredAt} {
+		if r.IsZero() {
+			continue
+		}
+
+		// a non-zero range never matches the orders not shipped or delivered yet, stored as NULL
+		where = append(where, column+` IS NOT NULL`)
+		if !r.From.IsZero() {
+			where = append(where, column+` >= ?`)
+			args = append(args, formatTime(r.From))
+		}
+		if !r.To.IsZero() {
+			where = append(where, column+` < ?`)
+			args = append(args, formatTime(r.To))
+		}
+	}
+
+	return where, args
+}
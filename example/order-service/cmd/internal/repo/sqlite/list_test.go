@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/organization/order-service"
+)
+
+func TestSQLite_List(t *testing.T) {
+	ctx := context.Background()
+	repo := getSQLite(t, getDB(t))
+	clock := order.NewFakeClock(time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC))
+
+	var placed []*order.Order
+	for i := 0; i < 5; i++ {
+		o := getPlacedOrder(t, clock)
+		if i%2 == 0 {
+			if err := o.MarkAsShipped(clock); err != nil {
+				t.Fatalf("could not mark order as shipped: %s", err)
+			}
+		}
+		if err := repo.Add(ctx, o); err != nil {
+			t.Fatalf("could not add order: %s", err)
+		}
+		placed = append([]*order.Order{o}, placed...)
+		clock.Advance(time.Hour)
+	}
+
+	t.Run("filter", func(t *testing.T) {
+		f := order.Filter{Statuses: []order.Status{order.Shipped}, ShippedAt: order.TimeRange{To: clock.Now()}}
+		p, err := repo.List(ctx, f, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		matchesIDsHelper(t, []*order.Order{placed[0], placed[2], placed[4]}, p.Orders)
+		if len(p.Orders[0].Items) != 1 {
+			t.Fatalf("could not match items: %v", p.Orders[0].Items)
+		}
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		f := order.Filter{Limit: 2}
+
+		first, err := repo.List(ctx, f, order.Cursor{})
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+		matchesIDsHelper(t, placed[:2], first.Orders)
+
+		second, err := repo.List(ctx, f, first.Next)
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+		matchesIDsHelper(t, placed[2:4], second.Orders)
+
+		back, err := repo.List(ctx, f, second.Previous)
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+		matchesIDsHelper(t, placed[:2], back.Orders)
+		if !back.Previous.IsZero() {
+			t.Fatalf("could not match the first page: %v", back.Previous)
+		}
+	})
+}
+
+func matchesIDsHelper(t *testing.T, want, got []*order.Order) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("could not match number of orders: got %d, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		if got[i].ID != want[i].ID {
+			t.Fatalf("could not match order %d: got %s, want %s", i, got[i].ID, want[i].ID)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrations holds the schema of the database, as golang-migrate files
+//
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+const (
+	createMigrationsTableQuery = `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+
+	selectVersionQuery = `SELECT version, dirty FROM schema_migrations`
+
+	deleteVersionQuery = `DELETE FROM schema_migrations`
+
+	insertVersionQuery = `INSERT INTO schema_migrations (version, dirty) VALUES (?, FALSE)`
+)
+
+// Migrate applies the up migrations the database is missing, each within its own transaction
+// The applied version is tracked in the schema_migrations table the way golang-migrate does,
+// so that the database can be migrated by either, since edge deployments cannot run the migrate container
+func Migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, createMigrationsTableQuery); err != nil {
+		return fmt.Errorf("could not create migrations table: %w", err)
+	}
+
+	var (
+		current int64
+		dirty   bool
+	)
+	switch err := db.QueryRowContext(ctx, selectVersionQuery).Scan(&current, &dirty); {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return fmt.Errorf("could not read migrations version: %w", err)
+	case dirty:
+		return fmt.Errorf("could not migrate dirty database at version %d", current)
+	}
+
+	files, err := fs.Glob(migrations, "migrations/*.up.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "migrations/")
+		version, err := strconv.ParseInt(name[:strings.Index(name, "_")], 10, 64)
+		if err != nil {
+			return fmt.Errorf("could not parse version of migration %s: %w", name, err)
+		}
+
+		if version <= current {
+			continue
+		}
+
+		if err := migrate(ctx, db, file, version); err != nil {
+			return fmt.Errorf("could not apply migration %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// migrate applies the migration file and records its version within one transaction
+func migrate(ctx context.Context, db *sql.DB, file string, version int64) error {
+	query, err := migrations.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, string(query)); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, deleteVersionQuery); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, insertVersionQuery, version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,30 @@
+CREATE TABLE orders
+(
+    id                   TEXT PRIMARY KEY,
+    number               TEXT    NOT NULL,
+    status               TEXT    NOT NULL,
+    placed_by            TEXT    NOT NULL,
+    placed_at            TEXT    NOT NULL,
+    shipped_at           TEXT,
+    delivered_at         TEXT,
+    cancelled_at         TEXT,
+    cancellation_reason  TEXT,
+    version              INTEGER NOT NULL DEFAULT 1,
+    shipping_recipient   TEXT,
+    shipping_lines       TEXT,
+    shipping_city        TEXT,
+    shipping_postal_code TEXT,
+    shipping_country     TEXT
+);
+
+CREATE UNIQUE INDEX orders_number_key ON orders (number);
+
+CREATE INDEX orders_placed_at_idx ON orders (placed_at DESC, id DESC);
+
+CREATE INDEX orders_placed_by_placed_at_idx ON orders (placed_by, placed_at DESC, id DESC);
+
+CREATE INDEX orders_status_placed_at_idx ON orders (status, placed_at DESC, id DESC);
+
+CREATE INDEX orders_shipped_at_idx ON orders (shipped_at);
+
+CREATE INDEX orders_delivered_at_idx ON orders (delivered_at);
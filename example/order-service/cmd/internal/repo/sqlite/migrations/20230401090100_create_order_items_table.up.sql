@@ -0,0 +1,11 @@
+CREATE TABLE order_items
+(
+    order_id            TEXT    NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    position            INTEGER NOT NULL,
+    sku                 TEXT    NOT NULL,
+    quantity            INTEGER NOT NULL CHECK (quantity > 0),
+    unit_price_amount   INTEGER NOT NULL CHECK (unit_price_amount >= 0),
+    unit_price_currency TEXT    NOT NULL,
+    PRIMARY KEY (order_id, position),
+    UNIQUE (order_id, sku)
+);
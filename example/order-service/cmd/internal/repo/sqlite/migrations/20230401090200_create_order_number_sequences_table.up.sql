@@ -0,0 +1,5 @@
+CREATE TABLE order_number_sequences
+(
+    tenant TEXT PRIMARY KEY,
+    value  INTEGER NOT NULL
+);
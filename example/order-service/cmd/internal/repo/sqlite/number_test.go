@@ -0,0 +1,38 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gologTest "github.com/damianopetrungaro/golog/test"
+	"github.com/organization/order-service"
+)
+
+func TestNumberAllocator_Allocate(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC)
+	formats := order.NumberFormats{"default": order.DefaultNumberFormat}
+	numbers := NewNumberAllocator(getDB(t), formats, gologTest.NewNullLogger())
+
+	for seq := uint64(1); seq <= 3; seq++ {
+		n, err := numbers.Allocate(ctx, "default", at)
+		if err != nil {
+			t.Fatalf("could not allocate number: %s", err)
+		}
+
+		want, err := order.DefaultNumberFormat.Format(at, seq)
+		if err != nil {
+			t.Fatalf("could not format number: %s", err)
+		}
+
+		if n != want {
+			t.Fatalf("could not match number: got %s, want %s", n, want)
+		}
+	}
+
+	if _, err := numbers.Allocate(ctx, "unknown", at); !errors.Is(err, order.ErrNumberNotAllocated) {
+		t.Fatalf("could not match error: %s", err)
+	}
+}
@@ -0,0 +1,279 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/organization/order-service"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var (
+	_ order.Repo = &SQLite{}
+)
+
+// timeLayout is the layout times are stored with, always in UTC
+// Its width is fixed, so that stored times sort as they would as times
+const timeLayout = "2006-01-02 15:04:05.000000000"
+
+const (
+	selectOrderQuery = `SELECT id, number, status, placed_by, placed_at, shipped_at, delivered_at, cancelled_at, cancellation_reason, version,
+shipping_recipient, shipping_lines, shipping_city, shipping_postal_code, shipping_country FROM orders`
+
+	insertOrderQuery = `INSERT INTO orders (number, status, placed_by, placed_at, shipped_at, delivered_at, cancelled_at, cancellation_reason, version,
+shipping_recipient, shipping_lines, shipping_city, shipping_postal_code, shipping_country, id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	updateOrderQuery = `UPDATE orders SET number = ?, status = ?, placed_by = ?, placed_at = ?, shipped_at = ?, delivered_at = ?, cancelled_at = ?,
+cancellation_reason = ?, version = ?, shipping_recipient = ?, shipping_lines = ?, shipping_city = ?, shipping_postal_code = ?, shipping_country = ?
+WHERE id = ? AND version = ?`
+)
+
+// SQLite represents a database layer for the order.Repo, meant for single-node deployments
+type SQLite struct {
+	db     *sql.DB
+	logger golog.Logger
+}
+
+// Open opens the SQLite database file at path
+// Transactions take the write lock as they begin and wait for it when busy,
+// so that concurrent writers are serialized instead of failing
+func Open(path string) (*sql.DB, error) {
+	q := url.Values{}
+	q.Add("_pragma", "foreign_keys(1)")
+	q.Add("_pragma", "busy_timeout(5000)")
+	q.Add("_pragma", "journal_mode(WAL)")
+	q.Set("_txlock", "immediate")
+
+	return sql.Open("sqlite", "file:"+path+"?"+q.Encode())
+}
+
+// New returns a database integration layer implementing order.Repo
+func New(db *sql.DB, logger golog.Logger) *SQLite {
+	return &SQLite{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Get queries an order and its items from the database, within a read transaction
+func (s *SQLite) Get(ctx context.Context, id order.ID) (*order.Order, error) {
+	var o *order.Order
+	err := s.read(ctx, func(tx *sql.Tx) error {
+		var err error
+		if o, err = scanOrder(tx.QueryRowContext(ctx, selectOrderQuery+` WHERE id = ?`, id.String())); err != nil {
+			return err
+		}
+
+		o.Items, err = getItems(ctx, tx, o.ID)
+		return err
+	})
+	if err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "order was not read from the database")
+		return nil, order.ErrNotFound
+	}
+
+	return o, nil
+}
+
+// read calls fn within a read transaction, so that the orders and their items are read from the same snapshot
+// and a concurrent Add replacing the items of an order is never seen halfway
+func (s *SQLite) read(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	// a read only transaction is started as deferred, so that it does not wait for the writers
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Add inserts an order to the database, or updates it if its version matches the stored one
+// It increments the version of the order once stored
+func (s *SQLite) Add(ctx context.Context, o *order.Order) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "transaction was not started")
+		return order.ErrNotAdded
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := save(ctx, tx, o); err != nil {
+		if errors.Is(err, order.ErrConcurrentModification) {
+			s.logger.With(golog.Err(err)).Warn(ctx, "order was modified concurrently in the database")
+			return fmt.Errorf("%w: %w", order.ErrNotAdded, err)
+		}
+		if errors.Is(err, order.ErrDuplicateNumber) {
+			s.logger.With(golog.Err(err)).Warn(ctx, "order number was already taken in the database")
+			return fmt.Errorf("%w: %w", order.ErrNotAdded, err)
+		}
+		s.logger.With(golog.Err(err)).Error(ctx, "order was not inserted in the database")
+		return order.ErrNotAdded
+	}
+
+	if err := saveItems(ctx, tx, o.ID, o.Items); err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "order items were not inserted in the database")
+		return order.ErrNotAdded
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "transaction was not committed")
+		return order.ErrNotAdded
+	}
+
+	o.Version++
+	return nil
+}
+
+// save inserts the order when it was never stored, otherwise updates it only if the stored version matches
+func save(ctx context.Context, tx *sql.Tx, o *order.Order) error {
+	args := orderArgs(o)
+	if o.Version == 0 {
+		if _, err := tx.ExecContext(ctx, insertOrderQuery, args...); err != nil {
+			var sqliteErr *sqlite.Error
+			if !errors.As(err, &sqliteErr) {
+				return err
+			}
+			switch {
+			case sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+				return order.ErrConcurrentModification
+			case sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE && strings.Contains(sqliteErr.Error(), "orders.number"):
+				return order.ErrDuplicateNumber
+			}
+			return err
+		}
+
+		return nil
+	}
+
+	res, err := tx.ExecContext(ctx, updateOrderQuery, append(args, o.Version)...)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return order.ErrConcurrentModification
+	}
+
+	return nil
+}
+
+// orderArgs returns the values of the columns of the order, as listed by insertOrderQuery and updateOrderQuery
+// times not reached yet and a missing shipping address are stored as NULL
+func orderArgs(o *order.Order) []any {
+	a := o.ShippingAddress
+	return []any{
+		o.Number.String(),
+		o.Status.String(),
+		o.PlacedBy.String(),
+		formatTime(o.PlacedAt),
+		nullTime(o.ShippedAt),
+		nullTime(o.DeliveredAt),
+		nullTime(o.CancelledAt),
+		nullString(o.CancellationReason.String()),
+		o.Version + 1,
+		nullString(a.Recipient()),
+		nullString(strings.Join(a.Lines(), "\n")),
+		nullString(a.City()),
+		nullString(a.PostalCode()),
+		nullString(a.Country()),
+		o.ID.String(),
+	}
+}
+
+// scanOrder maps a row selected by selectOrderQuery to an order, without its items
+func scanOrder(row interface{ Scan(...any) error }) (*order.Order, error) {
+	var (
+		o                                           order.Order
+		id, number, status, placedBy, placedAt      string
+		shippedAt, deliveredAt, cancelledAt, reason sql.NullString
+		recipient, lines, city, postalCode, country sql.NullString
+	)
+	if err := row.Scan(&id, &number, &status, &placedBy, &placedAt, &shippedAt, &deliveredAt, &cancelledAt, &reason, &o.Version,
+		&recipient, &lines, &city, &postalCode, &country); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if o.ID, err = order.ParseID(id); err != nil {
+		return nil, err
+	}
+	if o.PlacedBy, err = order.ParseUserID(placedBy); err != nil {
+		return nil, err
+	}
+	if o.PlacedAt, err = parseTime(placedAt); err != nil {
+		return nil, err
+	}
+	if o.ShippedAt, err = parseNullTime(shippedAt); err != nil {
+		return nil, err
+	}
+	if o.DeliveredAt, err = parseNullTime(deliveredAt); err != nil {
+		return nil, err
+	}
+	if o.CancelledAt, err = parseNullTime(cancelledAt); err != nil {
+		return nil, err
+	}
+
+	if country.Valid {
+		if o.ShippingAddress, err = order.ParseAddress(recipient.String, strings.Split(lines.String, "\n"), city.String, postalCode.String, country.String); err != nil {
+			return nil, err
+		}
+	}
+
+	o.Number = order.Number(number)
+	o.Status = order.Status(status)
+	o.CancellationReason = order.CancellationReason(reason.String)
+
+	return &o, nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func parseTime(s string) (time.Time, error) {
+	return time.ParseInLocation(timeLayout, s, time.UTC)
+}
+
+// nullTime returns NULL for the zero time
+func nullTime(t time.Time) sql.NullString {
+	if t.IsZero() {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: formatTime(t), Valid: true}
+}
+
+// parseNullTime returns the zero time for NULL
+func parseNullTime(s sql.NullString) (time.Time, error) {
+	if !s.Valid {
+		return time.Time{}, nil
+	}
+
+	return parseTime(s.String)
+}
+
+// nullString returns NULL for the empty string
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,198 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	gologTest "github.com/damianopetrungaro/golog/test"
+	"github.com/google/uuid"
+	"github.com/organization/order-service"
+	"github.com/organization/order-service/cmd/internal/repo/repotest"
+)
+
+func TestSQLite_Contract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) order.Repo {
+		return getSQLite(t, getDB(t))
+	})
+}
+
+func TestSQLite_Add_NullableTimes(t *testing.T) {
+	ctx := context.Background()
+	db := getDB(t)
+	repo := getSQLite(t, db)
+
+	clock := order.NewFakeClock(time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC))
+	o := getPlacedOrder(t, clock)
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	if n := countNullTimesHelper(t, db, o.ID); n != 3 {
+		t.Fatalf("could not match times of a placed order stored as NULL: %d", n)
+	}
+
+	clock.Advance(time.Hour)
+	if err := o.MarkAsShipped(clock); err != nil {
+		t.Fatalf("could not mark order as shipped: %s", err)
+	}
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	if n := countNullTimesHelper(t, db, o.ID); n != 2 {
+		t.Fatalf("could not match times of a shipped order stored as NULL: %d", n)
+	}
+
+	found, err := repo.Get(ctx, o.ID)
+	if err != nil {
+		t.Fatalf("could not get order: %s", err)
+	}
+
+	if !found.ShippedAt.Equal(clock.Now()) || !found.DeliveredAt.IsZero() || !found.CancelledAt.IsZero() {
+		t.Fatalf("could not match times: %v", found)
+	}
+}
+
+func TestSQLite_Get_ConcurrentAdd(t *testing.T) {
+	ctx := context.Background()
+	db := getDB(t)
+	repo := getSQLite(t, db)
+
+	o := getPlacedOrder(t, order.SystemClock{})
+	if err := repo.Add(ctx, o); err != nil {
+		t.Fatalf("could not add order: %s", err)
+	}
+
+	// the order holds one item in odd versions and three in even ones, so that a torn read is told apart
+	one, three := o.Items, append([]order.LineItem(nil), o.Items...)
+	for _, sku := range []order.SKU{"SKU-SECOND", "SKU-THIRD"} {
+		three = append(three, order.LineItem{SKU: sku, Quantity: 1, UnitPrice: order.NewMoney(399, order.EUR)})
+	}
+	itemsOf := func(version int) int {
+		if version%2 == 1 {
+			return len(one)
+		}
+		return len(three)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				found, err := repo.Get(ctx, o.ID)
+				if err != nil {
+					t.Errorf("could not get order: %s", err)
+					return
+				}
+				if len(found.Items) != itemsOf(found.Version) {
+					t.Errorf("could not match items of version %d: %d", found.Version, len(found.Items))
+					return
+				}
+
+				page, err := repo.List(ctx, order.Filter{PlacedBy: o.PlacedBy}, order.Cursor{})
+				if err != nil {
+					t.Errorf("could not list orders: %s", err)
+					return
+				}
+				for _, found := range page.Orders {
+					if len(found.Items) != itemsOf(found.Version) {
+						t.Errorf("could not match listed items of version %d: %d", found.Version, len(found.Items))
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 100; i++ {
+		o.Items = three
+		if itemsOf(o.Version+1) == len(one) {
+			o.Items = one
+		}
+		if err := repo.Add(ctx, o); err != nil {
+			t.Errorf("could not add order: %s", err)
+			break
+		}
+	}
+	close(done)
+	wg.Wait()
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	db := getDB(t)
+
+	if err := Migrate(ctx, db); err != nil {
+		t.Fatalf("could not migrate an up to date database: %s", err)
+	}
+
+	var version int64
+	if err := db.QueryRowContext(ctx, selectVersionQuery).Scan(&version, new(bool)); err != nil {
+		t.Fatalf("could not read version: %s", err)
+	}
+
+	if version != 20230401090200 {
+		t.Fatalf("could not match version: %d", version)
+	}
+}
+
+func getDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := Open(filepath.Join(t.TempDir(), "orders.db"))
+	if err != nil {
+		t.Fatalf("could not open database: %s", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("could not migrate database: %s", err)
+	}
+
+	return db
+}
+
+func getSQLite(t *testing.T, db *sql.DB) *SQLite {
+	t.Helper()
+
+	return New(db, gologTest.NewNullLogger())
+}
+
+func getPlacedOrder(t *testing.T, clock order.Clock) *order.Order {
+	t.Helper()
+
+	items := []order.LineItem{{SKU: order.SKU("SKU-" + uuid.NewString()[:8]), Quantity: 1, UnitPrice: order.NewMoney(1050, order.EUR)}}
+	o, err := order.Place(clock, order.NewID(), order.GenerateNumber(), order.UserID(uuid.New()), items)
+	if err != nil {
+		t.Fatalf("could not place order: %s", err)
+	}
+
+	return o
+}
+
+func countNullTimesHelper(t *testing.T, db *sql.DB, id order.ID) int {
+	t.Helper()
+
+	const query = `SELECT (shipped_at IS NULL) + (delivered_at IS NULL) + (cancelled_at IS NULL) FROM orders WHERE id = ?`
+	var n int
+	if err := db.QueryRow(query, id.String()).Scan(&n); err != nil {
+		t.Fatalf("could not query order: %s", err)
+	}
+
+	return n
+}
@@ -0,0 +1,85 @@
+package transaction
+
+import (
+	"context"
+	"sync"
+)
+
+type hooksKey struct{}
+
+// Hooks represents the callbacks to run once a transaction is committed, or once it is not
+// They let the decorators of a repository, such as the cache, defer their side effects
+// until the changes they depend on are visible to everyone, and to drop what they may have cached when the transaction is not committed
+type Hooks struct {
+	mu          sync.Mutex
+	fns         []func(ctx context.Context)
+	rollbackFns []func(ctx context.Context)
+}
+
+// WithHooks returns a context carrying the Hooks of a new transaction
+func WithHooks(ctx context.Context) (context.Context, *Hooks) {
+	h := &Hooks{}
+	return context.WithValue(ctx, hooksKey{}, h), h
+}
+
+// InTransaction reports whether ctx carries a transaction
+func InTransaction(ctx context.Context) bool {
+	_, ok := ctx.Value(hooksKey{}).(*Hooks)
+	return ok
+}
+
+// AfterCommit registers fn to run once the transaction carried by ctx is committed
+// fn runs right away when ctx carries no transaction, and never if the transaction is rolled back
+func AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	h, ok := ctx.Value(hooksKey{}).(*Hooks)
+	if !ok {
+		fn(ctx)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.fns = append(h.fns, fn)
+}
+
+// AfterRollback registers fn to run once the transaction carried by ctx is rolled back or fails to commit
+// fn is never run when ctx carries no transaction, the caller knowing right away whether its change was stored
+func AfterRollback(ctx context.Context, fn func(ctx context.Context)) {
+	h, ok := ctx.Value(hooksKey{}).(*Hooks)
+	if !ok {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.rollbackFns = append(h.rollbackFns, fn)
+}
+
+// Run calls the callbacks registered with AfterCommit in the order they were registered
+// It is meant to be called once the transaction is committed, the callbacks registered with AfterRollback being dropped
+func (h *Hooks) Run(ctx context.Context) {
+	h.mu.Lock()
+	fns := h.fns
+	h.fns, h.rollbackFns = nil, nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(ctx)
+	}
+}
+
+// Rollback calls the callbacks registered with AfterRollback in the order they were registered
+// It is meant to be called once the transaction is rolled back or fails to commit,
+// the callbacks registered with AfterCommit being dropped
+func (h *Hooks) Rollback(ctx context.Context) {
+	h.mu.Lock()
+	fns := h.rollbackFns
+	h.fns, h.rollbackFns = nil, nil
+	h.mu.Unlock()
+
+	for _, fn := range fns {
+		fn(ctx)
+	}
+}
@@ -0,0 +1,94 @@
+package transaction
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAfterCommit(t *testing.T) {
+	t.Run("without transaction", func(t *testing.T) {
+		var called bool
+		AfterCommit(context.Background(), func(context.Context) {
+			called = true
+		})
+
+		if !called {
+			t.Fatal("could not match callback run right away")
+		}
+	})
+
+	t.Run("within transaction", func(t *testing.T) {
+		ctx, hooks := WithHooks(context.Background())
+
+		var calls []int
+		AfterCommit(ctx, func(context.Context) {
+			calls = append(calls, 1)
+		})
+		AfterCommit(ctx, func(context.Context) {
+			calls = append(calls, 2)
+		})
+
+		if len(calls) != 0 {
+			t.Fatalf("could not match callbacks deferred until commit: %v", calls)
+		}
+
+		hooks.Run(context.Background())
+		hooks.Run(context.Background())
+
+		if len(calls) != 2 || calls[0] != 1 || calls[1] != 2 {
+			t.Fatalf("could not match callbacks run once in order: %v", calls)
+		}
+	})
+}
+
+func TestAfterRollback(t *testing.T) {
+	t.Run("without transaction", func(t *testing.T) {
+		var called bool
+		AfterRollback(context.Background(), func(context.Context) {
+			called = true
+		})
+
+		if called {
+			t.Fatal("could not match callback never run")
+		}
+	})
+
+	t.Run("rolled back", func(t *testing.T) {
+		ctx, hooks := WithHooks(context.Background())
+
+		var calls []string
+		AfterCommit(ctx, func(context.Context) {
+			calls = append(calls, "commit")
+		})
+		AfterRollback(ctx, func(context.Context) {
+			calls = append(calls, "rollback")
+		})
+
+		hooks.Rollback(context.Background())
+		hooks.Run(context.Background())
+		hooks.Rollback(context.Background())
+
+		if len(calls) != 1 || calls[0] != "rollback" {
+			t.Fatalf("could not match rollback callbacks run once: %v", calls)
+		}
+	})
+
+	t.Run("committed", func(t *testing.T) {
+		ctx, hooks := WithHooks(context.Background())
+
+		var calls []string
+		AfterCommit(ctx, func(context.Context) {
+			calls = append(calls, "commit")
+		})
+		AfterRollback(ctx, func(context.Context) {
+			calls = append(calls, "rollback")
+		})
+
+		hooks.Run(context.Background())
+		hooks.Rollback(context.Background())
+
+		if len(calls) != 1 || calls[0] != "commit" {
+			t.Fatalf("could not match rollback callbacks dropped once committed: %v", calls)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/damianopetrungaro/golog"
+	"github.com/damianopetrungaro/golog/opentelemetry"
+	_ "github.com/lib/pq"
+	"github.com/organization/order-service/cmd/internal/publisher"
+	"github.com/organization/order-service/cmd/internal/relay"
+	"github.com/organization/order-service/cmd/internal/repo/postgres"
+)
+
+// The relay polls the outbox table and publishes the stored events until it is stopped
+func main() {
+
+	var interval, minBackoff, maxBackoff time.Duration
+	var batchSize, maxAttempts int
+	flag.DurationVar(&interval, "interval", time.Second, "how often the outbox is polled")
+	flag.IntVar(&batchSize, "batch", 100, "how many events are published on every poll")
+	flag.DurationVar(&minBackoff, "min_backoff", time.Second, "wait before retrying an event the first time")
+	flag.DurationVar(&maxBackoff, "max_backoff", 5*time.Minute, "maximum wait before retrying an event")
+	flag.IntVar(&maxAttempts, "max_attempts", relay.DefaultMaxAttempts, "attempts after which an event failing to be published is parked")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	logger, flusher := newLogger()
+	defer func() {
+		flusher.Flush()
+	}()
+
+	db := newDB(ctx, logger)
+	r := relay.New(
+		postgres.NewOutbox(db, logger),
+		publisher.NewLogger(logger),
+		relay.ExponentialBackoff(minBackoff, maxBackoff),
+		logger,
+		relay.WithMaxAttempts(maxAttempts),
+	)
+
+	logger.Info(ctx, "relay was started")
+	if err := r.Run(ctx, interval, batchSize); err != nil && !errors.Is(err, context.Canceled) {
+		logger.With(golog.Err(err)).Error(ctx, "relay was stopped")
+		flusher.Flush()
+		os.Exit(1)
+	}
+
+	logger.Info(ctx, "relay was stopped")
+}
+
+func newLogger() (golog.Logger, golog.Flusher) {
+	lvl, err := golog.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		log.Fatalf("could not parse log level: %s", err)
+	}
+
+	return opentelemetry.NewProductionLogger(lvl)
+}
+
+func newDB(ctx context.Context, logger golog.Logger) *sql.DB {
+	const driver = "postgres"
+	db, err := sql.Open(driver, os.Getenv("DB_URL"))
+	if err != nil {
+		logger.With(golog.Err(err)).Fatal(ctx, "could not connect to database")
+	}
+
+	return db
+}
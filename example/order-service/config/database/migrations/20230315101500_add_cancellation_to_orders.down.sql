@@ -0,0 +1,3 @@
+ALTER TABLE orders
+    DROP COLUMN cancellation_reason,
+    DROP COLUMN cancelled_at;
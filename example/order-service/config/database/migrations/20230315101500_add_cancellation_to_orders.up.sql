@@ -0,0 +1,3 @@
+ALTER TABLE orders
+    ADD COLUMN cancelled_at        TIMESTAMP,
+    ADD COLUMN cancellation_reason VARCHAR(255);
@@ -0,0 +1,14 @@
+CREATE TABLE outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    aggregate_id    UUID         NOT NULL,
+    name            VARCHAR(255) NOT NULL,
+    payload         JSONB        NOT NULL,
+    occurred_at     TIMESTAMP    NOT NULL,
+    attempts        INTEGER      NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP    NOT NULL DEFAULT (now() AT TIME ZONE 'utc'),
+    last_error      TEXT,
+    dispatched_at   TIMESTAMP
+);
+
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE dispatched_at IS NULL;
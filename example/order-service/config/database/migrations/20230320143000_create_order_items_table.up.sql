@@ -0,0 +1,10 @@
+CREATE TABLE order_items
+(
+    order_id   UUID        NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    position   INTEGER     NOT NULL,
+    sku        VARCHAR(64) NOT NULL,
+    quantity   INTEGER     NOT NULL CHECK (quantity > 0),
+    unit_price BIGINT      NOT NULL CHECK (unit_price >= 0),
+    PRIMARY KEY (order_id, position),
+    UNIQUE (order_id, sku)
+);
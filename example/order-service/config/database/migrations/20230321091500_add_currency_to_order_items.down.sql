@@ -0,0 +1,5 @@
+ALTER TABLE order_items
+    DROP COLUMN unit_price_currency;
+
+ALTER TABLE order_items
+    RENAME COLUMN unit_price_amount TO unit_price;
\ No newline at end of file
@@ -0,0 +1,8 @@
+ALTER TABLE order_items
+    RENAME COLUMN unit_price TO unit_price_amount;
+
+ALTER TABLE order_items
+    ADD COLUMN unit_price_currency CHAR(3) NOT NULL DEFAULT 'EUR';
+
+ALTER TABLE order_items
+    ALTER COLUMN unit_price_currency DROP DEFAULT;
@@ -0,0 +1,6 @@
+ALTER TABLE orders
+    DROP COLUMN shipping_country,
+    DROP COLUMN shipping_postal_code,
+    DROP COLUMN shipping_city,
+    DROP COLUMN shipping_lines,
+    DROP COLUMN shipping_recipient;
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE orders
+    ADD COLUMN shipping_recipient   VARCHAR(100),
+    ADD COLUMN shipping_lines       TEXT,
+    ADD COLUMN shipping_city        VARCHAR(100),
+    ADD COLUMN shipping_postal_code VARCHAR(16),
+    ADD COLUMN shipping_country     CHAR(2);
@@ -0,0 +1 @@
+DROP INDEX orders_number_key;
\ No newline at end of file
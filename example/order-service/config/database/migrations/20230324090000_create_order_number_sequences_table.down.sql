@@ -0,0 +1 @@
+DROP TABLE order_number_sequences;
\ No newline at end of file
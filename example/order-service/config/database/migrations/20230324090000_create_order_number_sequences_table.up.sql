@@ -0,0 +1,5 @@
+CREATE TABLE order_number_sequences
+(
+    tenant VARCHAR(64) PRIMARY KEY,
+    value  BIGINT NOT NULL
+);
@@ -0,0 +1,5 @@
+DROP INDEX orders_delivered_at_idx;
+DROP INDEX orders_shipped_at_idx;
+DROP INDEX orders_status_placed_at_idx;
+DROP INDEX orders_placed_by_placed_at_idx;
+DROP INDEX orders_placed_at_idx;
\ No newline at end of file
@@ -0,0 +1,5 @@
+CREATE INDEX orders_placed_at_idx ON orders (placed_at DESC, id DESC);
+CREATE INDEX orders_placed_by_placed_at_idx ON orders (placed_by, placed_at DESC, id DESC);
+CREATE INDEX orders_status_placed_at_idx ON orders (status, placed_at DESC, id DESC);
+CREATE INDEX orders_shipped_at_idx ON orders (shipped_at);
+CREATE INDEX orders_delivered_at_idx ON orders (delivered_at);
@@ -0,0 +1,3 @@
+DROP TABLE order_snapshots;
+DROP TABLE order_events;
+DROP TABLE order_streams;
\ No newline at end of file
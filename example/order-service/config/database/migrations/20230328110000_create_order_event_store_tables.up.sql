@@ -0,0 +1,25 @@
+CREATE TABLE order_streams
+(
+    order_id UUID PRIMARY KEY,
+    number   VARCHAR(32) NOT NULL,
+    version  INTEGER     NOT NULL CHECK (version > 0),
+    CONSTRAINT order_streams_number_key UNIQUE (number)
+);
+
+CREATE TABLE order_events
+(
+    order_id    UUID         NOT NULL REFERENCES order_streams (order_id) ON DELETE CASCADE,
+    version     INTEGER      NOT NULL CHECK (version > 0),
+    name        VARCHAR(255) NOT NULL,
+    payload     JSONB        NOT NULL,
+    occurred_at TIMESTAMP    NOT NULL,
+    PRIMARY KEY (order_id, version)
+);
+
+CREATE TABLE order_snapshots
+(
+    order_id UUID PRIMARY KEY REFERENCES order_streams (order_id) ON DELETE CASCADE,
+    version  INTEGER   NOT NULL CHECK (version > 0),
+    payload  JSONB     NOT NULL,
+    taken_at TIMESTAMP NOT NULL DEFAULT (now() AT TIME ZONE 'utc')
+);
@@ -0,0 +1,5 @@
+DROP INDEX outbox_pending_idx;
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE dispatched_at IS NULL;
+
+ALTER TABLE outbox
+    DROP COLUMN parked_at;
@@ -0,0 +1,5 @@
+ALTER TABLE outbox
+    ADD COLUMN parked_at TIMESTAMP;
+
+DROP INDEX outbox_pending_idx;
+CREATE INDEX outbox_pending_idx ON outbox (id) WHERE dispatched_at IS NULL AND parked_at IS NULL;
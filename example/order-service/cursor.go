@@ -0,0 +1,81 @@
+package order
+
+import (
+	"time"
+)
+
+// Direction represents the way a Cursor pages through the orders
+type Direction int
+
+const (
+	// Forward pages towards the orders placed earlier
+	Forward Direction = iota
+	// Backward pages towards the orders placed later
+	Backward
+)
+
+// Cursor represents a position in a listing of orders, made of the placed at time and the ID of an order
+// The zero Cursor points to the first page
+type Cursor struct {
+	PlacedAt  time.Time
+	ID        ID
+	Direction Direction
+}
+
+// CursorOf returns the Cursor pointing next to the order in the given direction
+func CursorOf(o *Order, d Direction) Cursor {
+	return Cursor{PlacedAt: o.PlacedAt, ID: o.ID, Direction: d}
+}
+
+// IsZero reports whether c represents the zero Cursor
+func (c Cursor) IsZero() bool {
+	return c.ID.IsZero()
+}
+
+// Page represents a page of orders listed from a Cursor
+type Page struct {
+	Orders []*Order
+	// Next points to the page of the orders placed earlier, it is zero on the last page
+	Next Cursor
+	// Previous points to the page of the orders placed later, it is zero on the first page
+	Previous Cursor
+}
+
+// NewPage returns the Page of orders listed from the cursor
+// orders must be sorted in the direction of the cursor and hold one more order than size when a further page exists,
+// so that the storage can tell whether the page is the last one without counting the orders
+func NewPage(orders []*Order, size int, c Cursor) Page {
+	further := len(orders) > size
+	if further {
+		orders = orders[:size]
+	}
+
+	if c.Direction == Backward {
+		for i, j := 0, len(orders)-1; i < j; i, j = i+1, j-1 {
+			orders[i], orders[j] = orders[j], orders[i]
+		}
+	}
+
+	p := Page{Orders: orders}
+	if len(orders) == 0 {
+		// the orders around the cursor may have gone, the way back still starts from it
+		switch {
+		case c.IsZero():
+		case c.Direction == Forward:
+			p.Previous = Cursor{PlacedAt: c.PlacedAt, ID: c.ID, Direction: Backward}
+		default:
+			p.Next = Cursor{PlacedAt: c.PlacedAt, ID: c.ID, Direction: Forward}
+		}
+		return p
+	}
+
+	if (c.Direction == Forward && further) || (c.Direction == Backward && !c.IsZero()) {
+		p.Next = CursorOf(orders[len(orders)-1], Forward)
+	}
+
+	if (c.Direction == Backward && further) || (c.Direction == Forward && !c.IsZero()) {
+		p.Previous = CursorOf(orders[0], Backward)
+	}
+
+	return p
+}
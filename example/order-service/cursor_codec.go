@@ -0,0 +1,139 @@
+package order
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrCursorNotValid is returned when a cursor token was not issued by the codec or was tampered with
+	ErrCursorNotValid = errors.New("could not use cursor")
+	// ErrCursorKeyNotValid is returned when the key of a codec is too short to sign the cursor tokens
+	ErrCursorKeyNotValid = errors.New("could not use cursor key")
+)
+
+const (
+	// MinCursorKeyLength is the minimum number of bytes of the key signing the cursor tokens
+	MinCursorKeyLength = 32
+
+	cursorVersion       = 2
+	cursorDigestSize    = 8
+	cursorPayloadLength = 1 + 1 + 8 + 16 + cursorDigestSize
+	cursorSignatureSize = 16
+)
+
+// CursorCodec represents the encoding of cursors into opaque tokens
+// Tokens are signed with HMAC-SHA256, not encrypted: clients cannot forge a position, but can decode the one of a token
+// A token is bound to the filter it was issued for, so that it cannot be replayed against another listing
+type CursorCodec struct {
+	key []byte
+}
+
+// NewCursorCodec returns a CursorCodec signing the tokens with the key
+// It returns ErrCursorKeyNotValid when the key is shorter than MinCursorKeyLength
+func NewCursorCodec(key []byte) (*CursorCodec, error) {
+	if len(key) < MinCursorKeyLength {
+		return nil, fmt.Errorf("%w: key is %d bytes long, less than %d", ErrCursorKeyNotValid, len(key), MinCursorKeyLength)
+	}
+
+	return &CursorCodec{key: append([]byte(nil), key...)}, nil
+}
+
+// Encode returns the token of the cursor listing the orders matching the filter, the zero Cursor being encoded as an empty token
+// The placed at time is kept with microsecond precision, as stored by the databases
+func (c *CursorCodec) Encode(cur Cursor, f Filter) string {
+	if cur.IsZero() {
+		return ""
+	}
+
+	payload := make([]byte, cursorPayloadLength, cursorPayloadLength+cursorSignatureSize)
+	payload[0] = cursorVersion
+	payload[1] = byte(cur.Direction)
+	binary.BigEndian.PutUint64(payload[2:10], uint64(cur.PlacedAt.UnixMicro()))
+	copy(payload[10:26], cur.ID[:])
+	copy(payload[26:], filterDigest(f))
+
+	return base64.RawURLEncoding.EncodeToString(append(payload, c.sign(payload)...))
+}
+
+// Decode returns the cursor of the token, an empty token being decoded as the zero Cursor
+// It returns ErrCursorNotValid when the token was not issued by a codec with the same key, or for another filter
+func (c *CursorCodec) Decode(token string, f Filter) (Cursor, error) {
+	if token == "" {
+		return Cursor{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) != cursorPayloadLength+cursorSignatureSize {
+		return Cursor{}, fmt.Errorf("%w: malformed token", ErrCursorNotValid)
+	}
+
+	payload, signature := raw[:cursorPayloadLength], raw[cursorPayloadLength:]
+	if !hmac.Equal(signature, c.sign(payload)) {
+		return Cursor{}, fmt.Errorf("%w: signature mismatch", ErrCursorNotValid)
+	}
+
+	if payload[0] != cursorVersion {
+		return Cursor{}, fmt.Errorf("%w: unknown version %d", ErrCursorNotValid, payload[0])
+	}
+
+	d := Direction(payload[1])
+	if d != Forward && d != Backward {
+		return Cursor{}, fmt.Errorf("%w: unknown direction %d", ErrCursorNotValid, d)
+	}
+
+	if !hmac.Equal(payload[26:], filterDigest(f)) {
+		return Cursor{}, fmt.Errorf("%w: issued for another filter", ErrCursorNotValid)
+	}
+
+	cur := Cursor{
+		PlacedAt:  time.UnixMicro(int64(binary.BigEndian.Uint64(payload[2:10]))).UTC(),
+		Direction: d,
+	}
+	copy(cur.ID[:], payload[10:26])
+
+	return cur, nil
+}
+
+// sign returns the truncated HMAC of the payload
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+
+	return mac.Sum(nil)[:cursorSignatureSize]
+}
+
+// filterDigest returns the truncated SHA-256 of the criteria of the filter
+// The limit is left out, since the page size can change from a page to the next, and the statuses are sorted
+func filterDigest(f Filter) []byte {
+	statuses := make([]string, 0, len(f.Statuses))
+	for _, s := range f.Statuses {
+		statuses = append(statuses, s.String())
+	}
+	sort.Strings(statuses)
+
+	h := sha256.New()
+	h.Write(f.PlacedBy[:])
+	_ = binary.Write(h, binary.BigEndian, uint32(len(statuses)))
+	for _, s := range statuses {
+		_ = binary.Write(h, binary.BigEndian, uint32(len(s)))
+		h.Write([]byte(s))
+	}
+	for _, r := range []TimeRange{f.PlacedAt, f.ShippedAt, f.DeliveredAt} {
+		for _, t := range []time.Time{r.From, r.To} {
+			var n int64
+			if !t.IsZero() {
+				n = t.UnixNano()
+			}
+			_ = binary.Write(h, binary.BigEndian, n)
+		}
+	}
+
+	return h.Sum(nil)[:cursorDigestSize]
+}
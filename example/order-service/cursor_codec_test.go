@@ -0,0 +1,128 @@
+package order_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/organization/order-service"
+)
+
+func TestNewCursorCodec(t *testing.T) {
+	if _, err := NewCursorCodec(bytes.Repeat([]byte("k"), MinCursorKeyLength-1)); !errors.Is(err, ErrCursorKeyNotValid) {
+		t.Fatalf("could not match error: %s", err)
+	}
+
+	if _, err := NewCursorCodec(bytes.Repeat([]byte("k"), MinCursorKeyLength)); err != nil {
+		t.Fatalf("could not create cursor codec: %s", err)
+	}
+}
+
+func TestCursorCodec(t *testing.T) {
+	codec := cursorCodecHelper(t, "k")
+	at := time.Date(2023, time.March, 21, 9, 15, 0, 123456789, time.UTC)
+	c := Cursor{
+		PlacedAt:  at,
+		ID:        NewID(),
+		Direction: Backward,
+	}
+	f := Filter{
+		Statuses: []Status{Shipped, Delivered},
+		PlacedAt: TimeRange{From: at.Add(-24 * time.Hour)},
+		Limit:    10,
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		got, err := codec.Decode(codec.Encode(c, f), f)
+		if err != nil {
+			t.Fatalf("could not decode cursor: %s", err)
+		}
+
+		want := c
+		want.PlacedAt = c.PlacedAt.Truncate(time.Microsecond)
+		if got != want {
+			t.Errorf("could not match cursor: got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("zero", func(t *testing.T) {
+		if token := codec.Encode(Cursor{}, f); token != "" {
+			t.Fatalf("could not match empty token: %s", token)
+		}
+
+		got, err := codec.Decode("", f)
+		if err != nil || !got.IsZero() {
+			t.Fatalf("could not match zero cursor: %v, %s", got, err)
+		}
+	})
+
+	t.Run("same filter", func(t *testing.T) {
+		same := f
+		same.Statuses = []Status{Delivered, Shipped}
+		same.Limit = 20
+
+		if _, err := codec.Decode(codec.Encode(c, f), same); err != nil {
+			t.Fatalf("could not decode cursor for the same criteria: %s", err)
+		}
+	})
+
+	t.Run("other filter", func(t *testing.T) {
+		token := codec.Encode(c, f)
+		tests := map[string]func(*Filter){
+			"placed by":    func(f *Filter) { f.PlacedBy = UserID(NewID()) },
+			"statuses":     func(f *Filter) { f.Statuses = []Status{Shipped} },
+			"placed at":    func(f *Filter) { f.PlacedAt = TimeRange{} },
+			"shipped at":   func(f *Filter) { f.ShippedAt = TimeRange{To: at} },
+			"delivered at": func(f *Filter) { f.DeliveredAt = TimeRange{From: at} },
+		}
+
+		for name, change := range tests {
+			t.Run(name, func(t *testing.T) {
+				other := f
+				change(&other)
+
+				if _, err := codec.Decode(token, other); !errors.Is(err, ErrCursorNotValid) {
+					t.Fatalf("could not match error: %s", err)
+				}
+			})
+		}
+	})
+
+	t.Run("not valid", func(t *testing.T) {
+		token := codec.Encode(c, f)
+		raw, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil {
+			t.Fatalf("could not decode token: %s", err)
+		}
+		raw[1] ^= 1
+
+		tests := map[string]string{
+			"malformed":    "not a token!",
+			"truncated":    token[:len(token)-2],
+			"tampered":     base64.RawURLEncoding.EncodeToString(raw),
+			"other key":    cursorCodecHelper(t, "o").Encode(c, f),
+			"empty base64": "AAAA",
+		}
+
+		for name, token := range tests {
+			t.Run(name, func(t *testing.T) {
+				if _, err := codec.Decode(token, f); !errors.Is(err, ErrCursorNotValid) {
+					t.Fatalf("could not match error: %s", err)
+				}
+			})
+		}
+	})
+}
+
+func cursorCodecHelper(t *testing.T, key string) *CursorCodec {
+	t.Helper()
+
+	codec, err := NewCursorCodec(bytes.Repeat([]byte(key), MinCursorKeyLength))
+	if err != nil {
+		t.Fatalf("could not create cursor codec: %s", err)
+	}
+
+	return codec
+}
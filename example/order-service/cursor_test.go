@@ -0,0 +1,107 @@
+package order_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/organization/order-service"
+)
+
+func TestNewPage(t *testing.T) {
+	var orders []*Order
+	for i := 0; i < 3; i++ {
+		o := placeHelper(t)
+		o.PlacedAt = o.PlacedAt.Add(-time.Duration(i) * time.Hour)
+		orders = append(orders, o)
+	}
+	c := CursorOf(placeHelper(t), Forward)
+
+	tests := map[string]struct {
+		orders       []*Order
+		cursor       Cursor
+		want         []*Order
+		next         Cursor
+		previous     Cursor
+		wantNext     bool
+		wantPrevious bool
+	}{
+		"first page": {
+			orders:   orders,
+			cursor:   Cursor{},
+			want:     orders[:2],
+			next:     CursorOf(orders[1], Forward),
+			wantNext: true,
+		},
+		"only page": {
+			orders: orders[:2],
+			cursor: Cursor{},
+			want:   orders[:2],
+		},
+		"forward": {
+			orders:       orders,
+			cursor:       c,
+			want:         orders[:2],
+			next:         CursorOf(orders[1], Forward),
+			previous:     CursorOf(orders[0], Backward),
+			wantNext:     true,
+			wantPrevious: true,
+		},
+		"forward last page": {
+			orders:       orders[:1],
+			cursor:       c,
+			want:         orders[:1],
+			previous:     CursorOf(orders[0], Backward),
+			wantPrevious: true,
+		},
+		"backward": {
+			orders:       []*Order{orders[2], orders[1], orders[0]},
+			cursor:       Cursor{PlacedAt: c.PlacedAt, ID: c.ID, Direction: Backward},
+			want:         []*Order{orders[1], orders[2]},
+			next:         CursorOf(orders[2], Forward),
+			previous:     CursorOf(orders[1], Backward),
+			wantNext:     true,
+			wantPrevious: true,
+		},
+		"backward first page": {
+			orders:   []*Order{orders[1], orders[0]},
+			cursor:   Cursor{PlacedAt: c.PlacedAt, ID: c.ID, Direction: Backward},
+			want:     []*Order{orders[0], orders[1]},
+			next:     CursorOf(orders[1], Forward),
+			wantNext: true,
+		},
+		"empty forward": {
+			cursor:       c,
+			previous:     Cursor{PlacedAt: c.PlacedAt, ID: c.ID, Direction: Backward},
+			wantPrevious: true,
+		},
+		"empty backward": {
+			cursor:   Cursor{PlacedAt: c.PlacedAt, ID: c.ID, Direction: Backward},
+			next:     c,
+			wantNext: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := NewPage(append([]*Order(nil), tt.orders...), 2, tt.cursor)
+
+			if len(p.Orders) != len(tt.want) {
+				t.Fatalf("could not match number of orders: got %d, want %d", len(p.Orders), len(tt.want))
+			}
+
+			for i, o := range p.Orders {
+				if o != tt.want[i] {
+					t.Errorf("could not match order %d", i)
+				}
+			}
+
+			if p.Next.IsZero() == tt.wantNext || (tt.wantNext && p.Next != tt.next) {
+				t.Errorf("could not match next cursor: got %v, want %v", p.Next, tt.next)
+			}
+
+			if p.Previous.IsZero() == tt.wantPrevious || (tt.wantPrevious && p.Previous != tt.previous) {
+				t.Errorf("could not match previous cursor: got %v, want %v", p.Previous, tt.previous)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package order
+
+import "time"
+
+// Event represents a domain event recorded by the order aggregate
+type Event interface {
+	EventName() string
+	AggregateID() ID
+	OccurredAt() time.Time
+}
+
+// OrderPlaced is recorded when an order is placed
+type OrderPlaced struct {
+	OrderID  ID
+	Number   Number
+	PlacedBy UserID
+	Items    []LineItem
+	At       time.Time
+}
+
+// EventName returns the name of the event
+func (e OrderPlaced) EventName() string { return "order.placed" }
+
+// AggregateID returns the id of the order that recorded the event
+func (e OrderPlaced) AggregateID() ID { return e.OrderID }
+
+// OccurredAt returns the time the event occurred at
+func (e OrderPlaced) OccurredAt() time.Time { return e.At }
+
+// OrderShipped is recorded when an order is marked as shipped
+type OrderShipped struct {
+	OrderID ID
+	At      time.Time
+}
+
+// EventName returns the name of the event
+func (e OrderShipped) EventName() string { return "order.shipped" }
+
+// AggregateID returns the id of the order that recorded the event
+func (e OrderShipped) AggregateID() ID { return e.OrderID }
+
+// OccurredAt returns the time the event occurred at
+func (e OrderShipped) OccurredAt() time.Time { return e.At }
+
+// OrderDelivered is recorded when an order is marked as delivered
+type OrderDelivered struct {
+	OrderID ID
+	At      time.Time
+}
+
+// EventName returns the name of the event
+func (e OrderDelivered) EventName() string { return "order.delivered" }
+
+// AggregateID returns the id of the order that recorded the event
+func (e OrderDelivered) AggregateID() ID { return e.OrderID }
+
+// OccurredAt returns the time the event occurred at
+func (e OrderDelivered) OccurredAt() time.Time { return e.At }
+
+// OrderCancelled is recorded when an order is cancelled
+type OrderCancelled struct {
+	OrderID ID
+	Reason  CancellationReason
+	At      time.Time
+}
+
+// EventName returns the name of the event
+func (e OrderCancelled) EventName() string { return "order.cancelled" }
+
+// AggregateID returns the id of the order that recorded the event
+func (e OrderCancelled) AggregateID() ID { return e.OrderID }
+
+// OccurredAt returns the time the event occurred at
+func (e OrderCancelled) OccurredAt() time.Time { return e.At }
+
+// ShippingAddressChanged is recorded when the address an order ships to is changed
+type ShippingAddressChanged struct {
+	OrderID ID
+	Address Address
+	At      time.Time
+}
+
+// EventName returns the name of the event
+func (e ShippingAddressChanged) EventName() string { return "order.shipping_address_changed" }
+
+// AggregateID returns the id of the order that recorded the event
+func (e ShippingAddressChanged) AggregateID() ID { return e.OrderID }
+
+// OccurredAt returns the time the event occurred at
+func (e ShippingAddressChanged) OccurredAt() time.Time { return e.At }
+
+// PullEvents returns the events recorded by the order since the last pull and forgets them
+func (o *Order) PullEvents() []Event {
+	events := o.events
+	o.events, o.stored = nil, 0
+	return events
+}
+
+// Events returns the events recorded by the order since the last pull, without forgetting them
+// It allows a repository to store them together with the order
+func (o *Order) Events() []Event {
+	events := make([]Event, len(o.events))
+	copy(events, o.events)
+	return events
+}
+
+// UnstoredEvents returns the events recorded by the order and not marked as stored yet
+// It allows a repository appending the events, such as an event store or an outbox, to append them only once
+func (o *Order) UnstoredEvents() []Event {
+	events := make([]Event, len(o.events)-o.stored)
+	copy(events, o.events[o.stored:])
+	return events
+}
+
+// MarkEventsStored marks the events recorded so far as stored, once a repository committed them
+// They are still returned by Events and PullEvents, so that they can be published
+func (o *Order) MarkEventsStored() {
+	o.stored = len(o.events)
+}
+
+// StoredEvents returns the number of events recorded by the order and marked as stored
+func (o *Order) StoredEvents() int {
+	return o.stored
+}
+
+// UnmarkEventsStored marks the events recorded after the first n as not stored again,
+// once a repository rolled back the transaction they were stored in, n being the number stored before (see StoredEvents)
+func (o *Order) UnmarkEventsStored(n int) {
+	if n >= 0 && n < o.stored {
+		o.stored = n
+	}
+}
+
+func (o *Order) record(e Event) {
+	o.events = append(o.events, e)
+}
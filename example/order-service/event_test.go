@@ -0,0 +1,137 @@
+package order_test
+
+import (
+	"testing"
+
+	. "github.com/organization/order-service"
+)
+
+func TestOrder_PullEvents(t *testing.T) {
+	clock := clockHelper(t)
+	t.Run("lifecycle", func(t *testing.T) {
+		o := placeHelper(t)
+		if err := o.MarkAsShipped(clock); err != nil {
+			t.Fatalf("could not mark the order as shipped: %s", err)
+		}
+		if err := o.MarkAsDelivered(clock); err != nil {
+			t.Fatalf("could not mark the order as delivered: %s", err)
+		}
+
+		events := o.PullEvents()
+		if len(events) != 3 {
+			t.Fatalf("could not match number of events: %d", len(events))
+		}
+
+		placed, ok := events[0].(OrderPlaced)
+		if !ok {
+			t.Fatalf("could not match order placed event: %#v", events[0])
+		}
+		if placed.Number != o.Number || placed.PlacedBy != o.PlacedBy || len(placed.Items) != len(o.Items) || placed.OccurredAt() != o.PlacedAt {
+			t.Errorf("could not match order placed event: %#v", placed)
+		}
+
+		shipped, ok := events[1].(OrderShipped)
+		if !ok {
+			t.Fatalf("could not match order shipped event: %#v", events[1])
+		}
+		if shipped.OccurredAt() != o.ShippedAt {
+			t.Errorf("could not match order shipped event: %#v", shipped)
+		}
+
+		delivered, ok := events[2].(OrderDelivered)
+		if !ok {
+			t.Fatalf("could not match order delivered event: %#v", events[2])
+		}
+		if delivered.OccurredAt() != o.DeliveredAt {
+			t.Errorf("could not match order delivered event: %#v", delivered)
+		}
+
+		for _, e := range events {
+			if e.AggregateID() != o.ID {
+				t.Errorf("could not match aggregate id of %s: %s", e.EventName(), e.AggregateID())
+			}
+		}
+
+		if events := o.PullEvents(); len(events) != 0 {
+			t.Fatalf("could match events already pulled: %v", events)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		o := placeHelper(t)
+		_ = o.PullEvents()
+
+		if err := o.Cancel(clock, OutOfStock); err != nil {
+			t.Fatalf("could not cancel the order: %s", err)
+		}
+
+		events := o.PullEvents()
+		if len(events) != 1 {
+			t.Fatalf("could not match number of events: %d", len(events))
+		}
+
+		cancelled, ok := events[0].(OrderCancelled)
+		if !ok {
+			t.Fatalf("could not match order cancelled event: %#v", events[0])
+		}
+		if cancelled.Reason != OutOfStock {
+			t.Errorf("could not match order cancelled reason: %s", cancelled.Reason)
+		}
+	})
+
+	t.Run("rejected command", func(t *testing.T) {
+		o := placeHelper(t)
+		_ = o.PullEvents()
+
+		if err := o.MarkAsDelivered(clock); err == nil {
+			t.Fatalf("could mark a placed order as delivered")
+		}
+
+		if events := o.PullEvents(); len(events) != 0 {
+			t.Fatalf("could match events of a rejected command: %v", events)
+		}
+	})
+}
+
+func TestOrder_UnstoredEvents(t *testing.T) {
+	clock := clockHelper(t)
+	o := placeHelper(t)
+
+	if events := o.UnstoredEvents(); len(events) != 1 {
+		t.Fatalf("could not match number of unstored events: %d", len(events))
+	}
+
+	o.MarkEventsStored()
+	if events := o.UnstoredEvents(); len(events) != 0 {
+		t.Fatalf("could match events marked as stored: %v", events)
+	}
+
+	if err := o.MarkAsShipped(clock); err != nil {
+		t.Fatalf("could not mark the order as shipped: %s", err)
+	}
+
+	events := o.UnstoredEvents()
+	if len(events) != 1 {
+		t.Fatalf("could not match number of unstored events: %d", len(events))
+	}
+	if _, ok := events[0].(OrderShipped); !ok {
+		t.Fatalf("could not match order shipped event: %#v", events[0])
+	}
+
+	// the events stored in a transaction rolled back are stored again
+	stored := o.StoredEvents()
+	o.MarkEventsStored()
+	o.UnmarkEventsStored(stored)
+	if events := o.UnstoredEvents(); len(events) != 1 {
+		t.Fatalf("could not match number of unstored events once unmarked: %d", len(events))
+	}
+
+	// the events marked as stored are still pulled, so that they can be published
+	if events := o.PullEvents(); len(events) != 2 {
+		t.Fatalf("could not match number of events: %d", len(events))
+	}
+
+	if events := o.UnstoredEvents(); len(events) != 0 {
+		t.Fatalf("could match events already pulled: %v", events)
+	}
+}
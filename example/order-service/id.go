@@ -1,7 +1,9 @@
 package order
 
 import (
+	"encoding/binary"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,7 +16,7 @@ var (
 // ID represents an order id
 type ID uuid.UUID
 
-// NewID returns a new ID
+// NewID returns a new random ID (UUIDv4)
 func NewID() ID {
 	return ID(uuid.New())
 }
@@ -39,6 +41,33 @@ func (id ID) String() string {
 	return uuid.UUID(id).String()
 }
 
+// MarshalText encodes the ID as text
+func (id ID) MarshalText() ([]byte, error) {
+	return uuid.UUID(id).MarshalText()
+}
+
+// UnmarshalText decodes the ID from text
+func (id *ID) UnmarshalText(b []byte) error {
+	parsed, err := ParseID(string(b))
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+	return nil
+}
+
+// Time returns the creation time embedded in a time-ordered id
+// It reports false when the id is not a UUIDv7
+func (id ID) Time() (time.Time, bool) {
+	if uuid.UUID(id).Version() != 7 {
+		return time.Time{}, false
+	}
+
+	ms := int64(binary.BigEndian.Uint64(id[0:8]) >> 16)
+	return time.UnixMilli(ms), true
+}
+
 // UserID represents the user id that submitted the order
 type UserID uuid.UUID
 
@@ -61,3 +90,19 @@ func (id UserID) IsZero() bool {
 func (id UserID) String() string {
 	return uuid.UUID(id).String()
 }
+
+// MarshalText encodes the UserID as text
+func (id UserID) MarshalText() ([]byte, error) {
+	return uuid.UUID(id).MarshalText()
+}
+
+// UnmarshalText decodes the UserID from text
+func (id *UserID) UnmarshalText(b []byte) error {
+	parsed, err := ParseUserID(string(b))
+	if err != nil {
+		return err
+	}
+
+	*id = parsed
+	return nil
+}
@@ -0,0 +1,106 @@
+package order
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"sync"
+)
+
+var (
+	_ IDGenerator = V4Generator{}
+	_ IDGenerator = &V7Generator{}
+	_ IDGenerator = &SequenceGenerator{}
+)
+
+// IDGenerator represents a strategy generating the id of the orders
+type IDGenerator interface {
+	NewID() ID
+}
+
+// V4Generator represents an IDGenerator returning random UUIDv4
+type V4Generator struct{}
+
+// NewID returns a new random ID
+func (V4Generator) NewID() ID {
+	return NewID()
+}
+
+// V7Generator represents an IDGenerator returning time-ordered UUIDv7
+// Ids generated by the same generator are strictly increasing, even within the same millisecond
+type V7Generator struct {
+	clock Clock
+
+	mu     sync.Mutex
+	lastMs int64
+	seq    uint16
+}
+
+// NewV7Generator returns a V7Generator reading the creation time of the ids from the clock
+func NewV7Generator(clock Clock) *V7Generator {
+	return &V7Generator{clock: clock}
+}
+
+// NewID returns a new time-ordered ID
+func (g *V7Generator) NewID() ID {
+	var id ID
+	if _, err := rand.Read(id[:]); err != nil {
+		panic(err)
+	}
+
+	ms, seq := g.next(binary.BigEndian.Uint16(id[6:8]))
+
+	binary.BigEndian.PutUint64(id[0:8], uint64(ms)<<16)
+	id[6] = 0x70 | byte(seq>>8)&0x0f
+	id[7] = byte(seq)
+	id[8] = 0x80 | id[8]&0x3f
+
+	return id
+}
+
+// next returns the millisecond and the 12 bits sequence of the next id
+// The sequence starts from a random value in the lower half of its range on every new millisecond
+// and is incremented within the same one, moving to the next millisecond once exhausted
+func (g *V7Generator) next(random uint16) (int64, uint16) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ms := g.clock.Now().UnixMilli()
+	switch {
+	case ms > g.lastMs:
+		g.lastMs = ms
+		g.seq = random & 0x07ff
+	case g.seq < 0x0fff:
+		g.seq++
+	default:
+		g.lastMs++
+		g.seq = random & 0x07ff
+	}
+
+	return g.lastMs, g.seq
+}
+
+// SequenceGenerator represents a deterministic IDGenerator returning ids made of an incrementing counter
+// It is meant to be used in tests, where ids must be known in advance
+type SequenceGenerator struct {
+	mu   sync.Mutex
+	next uint64
+}
+
+// NewSequenceGenerator returns a SequenceGenerator whose first id holds the given value
+func NewSequenceGenerator(start uint64) *SequenceGenerator {
+	return &SequenceGenerator{next: start}
+}
+
+// NewID returns the next ID of the sequence, formatted as a UUIDv4 (e.g. 00000000-0000-4000-8000-000000000001)
+func (g *SequenceGenerator) NewID() ID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var id ID
+	binary.BigEndian.PutUint64(id[8:], g.next)
+	id[6] = 0x40
+	id[8] = 0x80 | id[8]&0x3f
+	g.next++
+
+	return id
+}
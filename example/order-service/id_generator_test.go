@@ -0,0 +1,95 @@
+package order_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	. "github.com/organization/order-service"
+
+	"github.com/google/uuid"
+)
+
+func TestV4Generator(t *testing.T) {
+	id := V4Generator{}.NewID()
+
+	if v := uuid.UUID(id).Version(); v != 4 {
+		t.Errorf("could not match version: %d", v)
+	}
+
+	if _, ok := id.Time(); ok {
+		t.Errorf("could extract the time from a random id: %s", id)
+	}
+}
+
+func TestV7Generator(t *testing.T) {
+	clock := clockHelper(t)
+	g := NewV7Generator(clock)
+
+	t.Run("version", func(t *testing.T) {
+		id := g.NewID()
+
+		if v := uuid.UUID(id).Version(); v != 7 {
+			t.Errorf("could not match version: %d", v)
+		}
+
+		if v := uuid.UUID(id).Variant(); v != uuid.RFC4122 {
+			t.Errorf("could not match variant: %s", v)
+		}
+	})
+
+	t.Run("time", func(t *testing.T) {
+		id := g.NewID()
+
+		got, ok := id.Time()
+		if !ok {
+			t.Fatalf("could not extract the time from id: %s", id)
+		}
+
+		if !got.Equal(clock.Now()) {
+			t.Error("could not match time")
+			t.Errorf("got: %s", got)
+			t.Errorf("want: %s", clock.Now())
+		}
+	})
+
+	t.Run("ordered", func(t *testing.T) {
+		prev := g.NewID()
+		for i := 0; i < 10_000; i++ {
+			if i%1000 == 0 {
+				clock.Advance(time.Millisecond)
+			}
+
+			id := g.NewID()
+			if bytes.Compare(prev[:], id[:]) >= 0 {
+				t.Fatalf("could not match ordered ids: %s is not after %s", id, prev)
+			}
+			prev = id
+		}
+	})
+
+	t.Run("clock moved backwards", func(t *testing.T) {
+		prev := g.NewID()
+		clock.Advance(-time.Hour)
+
+		if id := g.NewID(); bytes.Compare(prev[:], id[:]) >= 0 {
+			t.Fatalf("could not match ordered ids: %s is not after %s", id, prev)
+		}
+	})
+}
+
+func TestSequenceGenerator(t *testing.T) {
+	g := NewSequenceGenerator(1)
+
+	for _, want := range []string{
+		"00000000-0000-4000-8000-000000000001",
+		"00000000-0000-4000-8000-000000000002",
+		"00000000-0000-4000-8000-000000000003",
+	} {
+		if id := g.NewID(); id.String() != want {
+			t.Error("could not match id")
+			t.Errorf("got: %s", id)
+			t.Errorf("want: %s", want)
+		}
+	}
+}
@@ -91,3 +91,45 @@ func TestUserID_String(t *testing.T) {
 		t.Fatalf("want: %s", id.String())
 	}
 }
+
+func TestID_MarshalText(t *testing.T) {
+	id := NewID()
+	b, err := id.MarshalText()
+	if err != nil {
+		t.Fatalf("could not marshal id: %s", err)
+	}
+
+	var got ID
+	if err := got.UnmarshalText(b); err != nil {
+		t.Fatalf("could not unmarshal id: %s", err)
+	}
+
+	if got != id {
+		t.Error("could not match id once unmarshalled")
+		t.Errorf("got: %s", got)
+		t.Fatalf("want: %s", id)
+	}
+}
+
+func TestUserID_MarshalText(t *testing.T) {
+	id := UserID(uuid.New())
+	b, err := id.MarshalText()
+	if err != nil {
+		t.Fatalf("could not marshal user id: %s", err)
+	}
+
+	var got UserID
+	if err := got.UnmarshalText(b); err != nil {
+		t.Fatalf("could not unmarshal user id: %s", err)
+	}
+
+	if got != id {
+		t.Error("could not match user id once unmarshalled")
+		t.Errorf("got: %s", got)
+		t.Fatalf("want: %s", id)
+	}
+
+	if err := got.UnmarshalText([]byte("an invalid id")); !errors.Is(err, ErrUserIDNotParsed) {
+		t.Fatalf("could not match error: %s", err)
+	}
+}
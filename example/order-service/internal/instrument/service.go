@@ -13,9 +13,12 @@ var (
 // Service represents an interface matching internal.Service
 // it is used as base dor generating code for instrument purposes
 type Service interface {
-	Place(context.Context, order.Number, order.UserID) (*order.Order, error)
+	Place(context.Context, order.Tenant, order.UserID, []order.LineItem) (*order.Order, error)
 	MarkAsShipped(context.Context, order.ID) (*order.Order, error)
+	List(context.Context, order.Filter, order.Cursor) (order.Page, error)
 	MarkAsDelivered(context.Context, order.ID) (*order.Order, error)
+	Cancel(context.Context, order.ID, order.CancellationReason) (*order.Order, error)
+	ChangeShippingAddress(context.Context, order.ID, order.Address) (*order.Order, error)
 }
 
 // NewService returns an instrumented Service
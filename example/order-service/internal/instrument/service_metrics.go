@@ -39,6 +39,48 @@ func NewServiceWithPrometheus(base Service, instanceName string) ServiceWithProm
 	}
 }
 
+// Cancel implements Service
+func (_d ServiceWithPrometheus) Cancel(ctx context.Context, i1 order.ID, c1 order.CancellationReason) (op1 *order.Order, err error) {
+	_since := time.Now()
+	defer func() {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+
+		serviceDurationSummaryVec.WithLabelValues(_d.instanceName, "Cancel", result).Observe(time.Since(_since).Seconds())
+	}()
+	return _d.base.Cancel(ctx, i1, c1)
+}
+
+// ChangeShippingAddress implements Service
+func (_d ServiceWithPrometheus) ChangeShippingAddress(ctx context.Context, i1 order.ID, a1 order.Address) (op1 *order.Order, err error) {
+	_since := time.Now()
+	defer func() {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+
+		serviceDurationSummaryVec.WithLabelValues(_d.instanceName, "ChangeShippingAddress", result).Observe(time.Since(_since).Seconds())
+	}()
+	return _d.base.ChangeShippingAddress(ctx, i1, a1)
+}
+
+// List implements Service
+func (_d ServiceWithPrometheus) List(ctx context.Context, f1 order.Filter, c1 order.Cursor) (p1 order.Page, err error) {
+	_since := time.Now()
+	defer func() {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+
+		serviceDurationSummaryVec.WithLabelValues(_d.instanceName, "List", result).Observe(time.Since(_since).Seconds())
+	}()
+	return _d.base.List(ctx, f1, c1)
+}
+
 // MarkAsDelivered implements Service
 func (_d ServiceWithPrometheus) MarkAsDelivered(ctx context.Context, i1 order.ID) (op1 *order.Order, err error) {
 	_since := time.Now()
@@ -68,7 +110,7 @@ func (_d ServiceWithPrometheus) MarkAsShipped(ctx context.Context, i1 order.ID)
 }
 
 // Place implements Service
-func (_d ServiceWithPrometheus) Place(ctx context.Context, n1 order.Number, u1 order.UserID) (op1 *order.Order, err error) {
+func (_d ServiceWithPrometheus) Place(ctx context.Context, t1 order.Tenant, u1 order.UserID, la1 []order.LineItem) (op1 *order.Order, err error) {
 	_since := time.Now()
 	defer func() {
 		result := "ok"
@@ -78,5 +120,5 @@ func (_d ServiceWithPrometheus) Place(ctx context.Context, n1 order.Number, u1 o
 
 		serviceDurationSummaryVec.WithLabelValues(_d.instanceName, "Place", result).Observe(time.Since(_since).Seconds())
 	}()
-	return _d.base.Place(ctx, n1, u1)
+	return _d.base.Place(ctx, t1, u1, la1)
 }
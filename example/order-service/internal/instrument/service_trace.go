@@ -36,6 +36,78 @@ func NewServiceWithTracing(base Service, instance string, spanDecorator ...func(
 	return d
 }
 
+// Cancel implements Service
+func (_d ServiceWithTracing) Cancel(ctx context.Context, i1 order.ID, c1 order.CancellationReason) (op1 *order.Order, err error) {
+	ctx, _span := otel.Tracer(_d._instance).Start(ctx, "Service.Cancel")
+	defer func() {
+		if _d._spanDecorator != nil {
+			_d._spanDecorator(_span, map[string]interface{}{
+				"ctx": ctx,
+				"i1":  i1,
+				"c1":  c1}, map[string]interface{}{
+				"op1": op1,
+				"err": err})
+		} else if err != nil {
+			_span.RecordError(err)
+			_span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+
+		_span.End()
+	}()
+	return _d.Service.Cancel(ctx, i1, c1)
+}
+
+// ChangeShippingAddress implements Service
+func (_d ServiceWithTracing) ChangeShippingAddress(ctx context.Context, i1 order.ID, a1 order.Address) (op1 *order.Order, err error) {
+	ctx, _span := otel.Tracer(_d._instance).Start(ctx, "Service.ChangeShippingAddress")
+	defer func() {
+		if _d._spanDecorator != nil {
+			_d._spanDecorator(_span, map[string]interface{}{
+				"ctx": ctx,
+				"i1":  i1,
+				"a1":  a1}, map[string]interface{}{
+				"op1": op1,
+				"err": err})
+		} else if err != nil {
+			_span.RecordError(err)
+			_span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+
+		_span.End()
+	}()
+	return _d.Service.ChangeShippingAddress(ctx, i1, a1)
+}
+
+// List implements Service
+func (_d ServiceWithTracing) List(ctx context.Context, f1 order.Filter, c1 order.Cursor) (p1 order.Page, err error) {
+	ctx, _span := otel.Tracer(_d._instance).Start(ctx, "Service.List")
+	defer func() {
+		if _d._spanDecorator != nil {
+			_d._spanDecorator(_span, map[string]interface{}{
+				"ctx": ctx,
+				"f1":  f1,
+				"c1":  c1}, map[string]interface{}{
+				"p1":  p1,
+				"err": err})
+		} else if err != nil {
+			_span.RecordError(err)
+			_span.SetAttributes(
+				attribute.String("event", "error"),
+				attribute.String("message", err.Error()),
+			)
+		}
+
+		_span.End()
+	}()
+	return _d.Service.List(ctx, f1, c1)
+}
+
 // MarkAsDelivered implements Service
 func (_d ServiceWithTracing) MarkAsDelivered(ctx context.Context, i1 order.ID) (op1 *order.Order, err error) {
 	ctx, _span := otel.Tracer(_d._instance).Start(ctx, "Service.MarkAsDelivered")
@@ -83,14 +155,15 @@ func (_d ServiceWithTracing) MarkAsShipped(ctx context.Context, i1 order.ID) (op
 }
 
 // Place implements Service
-func (_d ServiceWithTracing) Place(ctx context.Context, n1 order.Number, u1 order.UserID) (op1 *order.Order, err error) {
+func (_d ServiceWithTracing) Place(ctx context.Context, t1 order.Tenant, u1 order.UserID, la1 []order.LineItem) (op1 *order.Order, err error) {
 	ctx, _span := otel.Tracer(_d._instance).Start(ctx, "Service.Place")
 	defer func() {
 		if _d._spanDecorator != nil {
 			_d._spanDecorator(_span, map[string]interface{}{
 				"ctx": ctx,
-				"n1":  n1,
-				"u1":  u1}, map[string]interface{}{
+				"t1":  t1,
+				"u1":  u1,
+				"la1": la1}, map[string]interface{}{
 				"op1": op1,
 				"err": err})
 		} else if err != nil {
@@ -103,5 +176,5 @@ func (_d ServiceWithTracing) Place(ctx context.Context, n1 order.Number, u1 orde
 
 		_span.End()
 	}()
-	return _d.Service.Place(ctx, n1, u1)
+	return _d.Service.Place(ctx, t1, u1, la1)
 }
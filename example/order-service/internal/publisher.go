@@ -0,0 +1,12 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/organization/order-service"
+)
+
+// Publisher represents the layer publishing domain events to downstream services
+type Publisher interface {
+	Publish(ctx context.Context, events ...order.Event) error
+}
@@ -13,73 +13,300 @@ var (
 	ErrNotPlaced            = errors.New("order could not be placed")
 	ErrNotMarkedAsShipped   = errors.New("order could not be marked as shipped")
 	ErrNotMarkedAsDelivered = errors.New("order could not be marked as delivered")
+	ErrNotCancelled         = errors.New("order could not be cancelled")
+	// ErrShippingAddressNotChanged is returned when the shipping address of the order could not be changed
+	ErrShippingAddressNotChanged = errors.New("order shipping address could not be changed")
+	// ErrNotListed is returned when the orders could not be listed
+	ErrNotListed = errors.New("orders could not be listed")
+	// ErrConflict is returned when the order was modified or locked concurrently, the use case can be retried
+	ErrConflict = errors.New("order was modified concurrently")
+	// ErrNumberTaken is returned when the number allocated was already taken by a stored order
+	// Retrying allocates the same number again, as its allocation was rolled back together with the order,
+	// so the number sequence of the tenant must be moved past the stored numbers first
+	ErrNumberTaken = errors.New("order number allocated was already taken, the number sequence of the tenant is behind")
 )
 
 // Service represent the application layer
 // it depends on the domain logic and can be used by any infrastructure layer as domain logic orchestrator
 type Service struct {
-	repo   order.Repo
-	logger golog.Logger
+	repo       order.Repo
+	lister     order.Lister
+	numbers    order.NumberAllocator
+	publisher  Publisher
+	logger     golog.Logger
+	clock      order.Clock
+	ids        order.IDGenerator
+	transactor Transactor
+	locker     order.Locker
+	lockMode   order.LockMode
+}
+
+// Option represents an optional configuration of the Service
+type Option func(*Service)
+
+// WithClock sets the clock used to timestamp the orders, the system clock is used by default
+func WithClock(clock order.Clock) Option {
+	return func(s *Service) {
+		s.clock = clock
+	}
+}
+
+// WithIDGenerator sets the strategy generating the id of the placed orders
+// time-ordered ids (UUIDv7) read from the clock of the Service are generated by default
+func WithIDGenerator(ids order.IDGenerator) Option {
+	return func(s *Service) {
+		s.ids = ids
+	}
+}
+
+// WithTransactor sets the unit of work the use cases run in
+// use cases run without transaction by default
+func WithTransactor(transactor Transactor) Option {
+	return func(s *Service) {
+		s.transactor = transactor
+	}
+}
+
+// WithLocker sets the layer locking the orders read by the use cases changing them, waiting for the locks as told by mode
+// it is meant to be used along with a Transactor holding the locks, orders are read without locking by default
+func WithLocker(locker order.Locker, mode order.LockMode) Option {
+	return func(s *Service) {
+		s.locker = locker
+		s.lockMode = mode
+	}
 }
 
 // NewService returns a new Service
-func NewService(repo order.Repo, logger golog.Logger) *Service {
-	return &Service{
-		repo:   repo,
-		logger: logger,
+func NewService(repo order.Repo, lister order.Lister, numbers order.NumberAllocator, publisher Publisher, logger golog.Logger, opts ...Option) *Service {
+	s := &Service{
+		repo:       repo,
+		lister:     lister,
+		numbers:    numbers,
+		publisher:  publisher,
+		logger:     logger,
+		clock:      order.SystemClock{},
+		transactor: noTransaction{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.ids == nil {
+		s.ids = order.NewV7Generator(s.clock)
 	}
+
+	return s
 }
 
-// Place places an order and store it in the repository
-func (s *Service) Place(ctx context.Context, n order.Number, uID order.UserID) (*order.Order, error) {
-	o := order.Place(n, uID)
+// Place places an order through the given tenant and store it in the repository
+// the order number is allocated from the sequence of the tenant, within the transaction storing the order
+func (s *Service) Place(ctx context.Context, tenant order.Tenant, uID order.UserID, items []order.LineItem) (*order.Order, error) {
+	var o *order.Order
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		n, err := s.numbers.Allocate(ctx, tenant, s.clock.Now())
+		if err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order number was not allocated")
+			return err
+		}
+
+		if o, err = order.Place(s.clock, s.ids.NewID(), n, uID, items); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order was not placed")
+			return err
+		}
+
+		if err := s.add(ctx, o); err != nil {
+			if errors.Is(err, order.ErrDuplicateNumber) {
+				s.logger.With(golog.Err(err), golog.String("number", n.String())).Error(ctx, "order number was already taken once placed")
+				return fmt.Errorf("%w: %s: %w", ErrNumberTaken, n, err)
+			}
+			s.logger.With(golog.Err(err)).Error(ctx, "order was not added once placed")
+			return err
+		}
 
-	if err := s.repo.Add(ctx, o); err != nil {
-		s.logger.With(golog.Err(err)).Error(ctx, "order was not added once placed")
+		return nil
+	})
+	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrNotPlaced, err)
 	}
 
+	s.publish(ctx, o)
+
 	return o, nil
 }
 
 // MarkAsShipped marks as shipped an order and store it in the repository
 func (s *Service) MarkAsShipped(ctx context.Context, id order.ID) (*order.Order, error) {
-	o, err := s.repo.Get(ctx, id)
-	if err != nil {
-		s.logger.With(golog.Err(err)).Error(ctx, "order was not found")
-		return nil, fmt.Errorf("%w: %w", ErrNotMarkedAsShipped, err)
-	}
+	o, err := s.change(ctx, id, func(ctx context.Context, o *order.Order) error {
+		if err := o.MarkAsShipped(s.clock); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order was not marked as shipped")
+			return err
+		}
 
-	if err := o.MarkAsShipped(); err != nil {
-		s.logger.With(golog.Err(err)).Error(ctx, "order was not marked as shipped")
-		return nil, fmt.Errorf("%w: %w", ErrNotMarkedAsShipped, err)
-	}
+		if err := s.add(ctx, o); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order was not added once marked as shipped")
+			return err
+		}
 
-	if err := s.repo.Add(ctx, o); err != nil {
-		s.logger.With(golog.Err(err)).Error(ctx, "order was not added once marked as shipped")
+		return nil
+	})
+	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrNotMarkedAsShipped, err)
 	}
 
+	s.publish(ctx, o)
+
 	return o, nil
 }
 
 // MarkAsDelivered marks as delivered an order and store it in the repository
 func (s *Service) MarkAsDelivered(ctx context.Context, id order.ID) (*order.Order, error) {
-	o, err := s.repo.Get(ctx, id)
+	o, err := s.change(ctx, id, func(ctx context.Context, o *order.Order) error {
+		if err := o.MarkAsDelivered(s.clock); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order was not marked as delivered")
+			return err
+		}
+
+		if err := s.add(ctx, o); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order was not added once marked as delivered")
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		s.logger.With(golog.Err(err)).Error(ctx, "order was not found")
 		return nil, fmt.Errorf("%w: %w", ErrNotMarkedAsDelivered, err)
 	}
 
-	if err := o.MarkAsDelivered(); err != nil {
-		s.logger.With(golog.Err(err)).Error(ctx, "order was not marked as delivered")
-		return nil, fmt.Errorf("%w: %w", ErrNotMarkedAsDelivered, err)
+	s.publish(ctx, o)
+
+	return o, nil
+}
+
+// Cancel cancels an order for the given reason and store it in the repository
+func (s *Service) Cancel(ctx context.Context, id order.ID, reason order.CancellationReason) (*order.Order, error) {
+	o, err := s.change(ctx, id, func(ctx context.Context, o *order.Order) error {
+		if err := o.Cancel(s.clock, reason); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order was not cancelled")
+			return err
+		}
+
+		if err := s.add(ctx, o); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order was not added once cancelled")
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotCancelled, err)
 	}
 
-	if err := s.repo.Add(ctx, o); err != nil {
-		s.logger.With(golog.Err(err)).Error(ctx, "order was not added once marked as delivered")
-		return nil, fmt.Errorf("%w: %w", ErrNotMarkedAsDelivered, err)
+	s.publish(ctx, o)
+
+	return o, nil
+}
+
+// ChangeShippingAddress changes the address an order ships to and store it in the repository
+func (s *Service) ChangeShippingAddress(ctx context.Context, id order.ID, address order.Address) (*order.Order, error) {
+	o, err := s.change(ctx, id, func(ctx context.Context, o *order.Order) error {
+		if err := o.ChangeShippingAddress(s.clock, address); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order shipping address was not changed")
+			return err
+		}
+
+		if err := s.add(ctx, o); err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order was not added once its shipping address changed")
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrShippingAddressNotChanged, err)
+	}
+
+	s.publish(ctx, o)
+
+	return o, nil
+}
+
+// List lists a page of the orders matching the filter, from the most recently placed
+func (s *Service) List(ctx context.Context, f order.Filter, c order.Cursor) (order.Page, error) {
+	p, err := s.lister.List(ctx, f, c)
+	if err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "orders were not listed")
+		return order.Page{}, fmt.Errorf("%w: %w", ErrNotListed, err)
+	}
+
+	return p, nil
+}
+
+// change reads the order and hands it to fn within one transaction, so that both run on the same connection
+// the order is locked until the transaction ends when a Locker is set
+// the events of the order are left to be published once the transaction is committed
+func (s *Service) change(ctx context.Context, id order.ID, fn func(ctx context.Context, o *order.Order) error) (*order.Order, error) {
+	var o *order.Order
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		if o, err = s.get(ctx, id); err != nil {
+			return err
+		}
+
+		return fn(ctx, o)
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return o, nil
 }
+
+// get reads the order from the repository, or locks it when a Locker is set
+// an order locked by someone else is wrapped in ErrConflict, so that callers can tell it apart and retry
+func (s *Service) get(ctx context.Context, id order.ID) (*order.Order, error) {
+	if s.locker == nil {
+		o, err := s.repo.Get(ctx, id)
+		if err != nil {
+			s.logger.With(golog.Err(err)).Error(ctx, "order was not found")
+			return nil, err
+		}
+
+		return o, nil
+	}
+
+	o, err := s.locker.GetForUpdate(ctx, id, s.lockMode)
+	if errors.Is(err, order.ErrLocked) {
+		s.logger.With(golog.Err(err)).Warn(ctx, "order was locked concurrently")
+		return nil, fmt.Errorf("%w: %w", ErrConflict, err)
+	}
+	if err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "order was not found")
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// add stores the order in the repository
+// a concurrent modification is wrapped in ErrConflict, so that callers can tell it apart and retry
+func (s *Service) add(ctx context.Context, o *order.Order) error {
+	err := s.repo.Add(ctx, o)
+	if errors.Is(err, order.ErrConcurrentModification) {
+		s.logger.With(golog.Err(err)).Warn(ctx, "order was modified concurrently")
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	}
+
+	return err
+}
+
+// publish hands the events recorded by the order to the publisher
+// a failure is only logged, since the order was already stored in the repository
+func (s *Service) publish(ctx context.Context, o *order.Order) {
+	events := o.PullEvents()
+	if len(events) == 0 {
+		return
+	}
+
+	if err := s.publisher.Publish(ctx, events...); err != nil {
+		s.logger.With(golog.Err(err)).Error(ctx, "order events were not published")
+	}
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"context"
 	"errors"
+	"fmt"
 	gologTest "github.com/damianopetrungaro/golog/test"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
@@ -12,6 +13,52 @@ import (
 )
 
 func TestService_Place(t *testing.T) {
+	t.Run("not valid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+
+		o, err := svc.Place(ctx, newTenant(t), newUserID(t), nil)
+		if !errors.Is(err, ErrNotPlaced) || !errors.Is(err, order.ErrNotPlaced) {
+			t.Fatalf("could not match placing order error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
+	t.Run("number not allocated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+
+		o, err := svc.Place(ctx, "marketplace", newUserID(t), newItems(t))
+		if !errors.Is(err, ErrNotPlaced) || !errors.Is(err, order.ErrNumberNotAllocated) {
+			t.Fatalf("could not match placing order error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
 	t.Run("not added", func(t *testing.T) {
 
 		ctrl := gomock.NewController(t)
@@ -21,15 +68,16 @@ func TestService_Place(t *testing.T) {
 
 		ctx := context.Background()
 		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
 		logger := gologTest.NewNullLogger()
 
 		repo.EXPECT().Add(ctx, gomock.Any()).Return(order.ErrNotAdded)
 
-		svc := NewService(repo, logger)
-		n := newOrderNumber(t)
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+		tenant := newTenant(t)
 		uID := newUserID(t)
 
-		o, err := svc.Place(ctx, n, uID)
+		o, err := svc.Place(ctx, tenant, uID, newItems(t))
 		if !errors.Is(err, ErrNotPlaced) {
 			t.Fatalf("could not match placing order error: %s", err)
 		}
@@ -39,6 +87,31 @@ func TestService_Place(t *testing.T) {
 		}
 	})
 
+	t.Run("number taken", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		repo.EXPECT().Add(ctx, gomock.Any()).Return(fmt.Errorf("%w: %w", order.ErrNotAdded, order.ErrDuplicateNumber))
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+
+		o, err := svc.Place(ctx, newTenant(t), newUserID(t), newItems(t))
+		if !errors.Is(err, ErrNotPlaced) || !errors.Is(err, ErrNumberTaken) || errors.Is(err, ErrConflict) {
+			t.Fatalf("could not match placing order error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
 	t.Run("placed", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		t.Cleanup(func() {
@@ -47,19 +120,34 @@ func TestService_Place(t *testing.T) {
 
 		ctx := context.Background()
 		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
 		logger := gologTest.NewNullLogger()
 
 		repo.EXPECT().Add(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
 
-		svc := NewService(repo, logger)
-		n := newOrderNumber(t)
+		clock := newClock(t)
+		ids := order.NewSequenceGenerator(1)
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger, WithClock(clock), WithIDGenerator(ids))
+		tenant := newTenant(t)
 		uID := newUserID(t)
 
-		o, err := svc.Place(ctx, n, uID)
+		o, err := svc.Place(ctx, tenant, uID, newItems(t))
 		if err != nil {
 			t.Fatalf("could not place order: %s", err)
 		}
 
+		if want := "00000000-0000-4000-8000-000000000001"; o.ID.String() != want {
+			t.Errorf("could not match id")
+			t.Errorf("got: %s", o.ID)
+			t.Errorf("want: %s", want)
+		}
+
+		n, err := order.DefaultNumberFormat.Format(clock.Now(), 1)
+		if err != nil {
+			t.Fatalf("could not format number: %s", err)
+		}
+
 		if o.Number != n {
 			t.Errorf("could not match number")
 			t.Errorf("got: %s", o.Number)
@@ -78,10 +166,78 @@ func TestService_Place(t *testing.T) {
 			t.Errorf("want: %s", uID)
 		}
 
-		if time.Until(o.PlacedAt) > time.Second {
+		if !o.PlacedAt.Equal(clock.Now()) {
 			t.Errorf("could not match placed at time: %s", o.PlacedAt)
 		}
 	})
+
+	t.Run("placed with time-ordered id", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		repo.EXPECT().Add(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+
+		clock := newClock(t)
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger, WithClock(clock))
+
+		o, err := svc.Place(ctx, newTenant(t), newUserID(t), newItems(t))
+		if err != nil {
+			t.Fatalf("could not place order: %s", err)
+		}
+
+		createdAt, ok := o.ID.Time()
+		if !ok {
+			t.Fatalf("could not extract the time from id: %s", o.ID)
+		}
+
+		if !createdAt.Equal(o.PlacedAt) {
+			t.Errorf("could not match id time")
+			t.Errorf("got: %s", createdAt)
+			t.Errorf("want: %s", o.PlacedAt)
+		}
+	})
+
+	t.Run("not published", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		repo.EXPECT().Add(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, events ...order.Event) error {
+			if len(events) != 1 {
+				t.Errorf("could not match number of events: %d", len(events))
+			}
+			if _, ok := events[0].(order.OrderPlaced); !ok {
+				t.Errorf("could not match order placed event: %#v", events[0])
+			}
+			return errors.New("broker unavailable")
+		})
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+
+		o, err := svc.Place(ctx, newTenant(t), newUserID(t), newItems(t))
+		if err != nil {
+			t.Fatalf("could not place order once stored: %s", err)
+		}
+
+		if events := o.PullEvents(); len(events) != 0 {
+			t.Fatalf("could match events not handed to the publisher: %v", events)
+		}
+	})
 }
 
 func TestService_MarkAsShipped(t *testing.T) {
@@ -93,9 +249,10 @@ func TestService_MarkAsShipped(t *testing.T) {
 
 		ctx := context.Background()
 		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
 		logger := gologTest.NewNullLogger()
 
-		svc := NewService(repo, logger)
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
 		id := newID(t)
 
 		repo.EXPECT().Find(ctx, id).Return(nil, order.ErrNotFound)
@@ -118,6 +275,7 @@ func TestService_MarkAsShipped(t *testing.T) {
 
 		ctx := context.Background()
 		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
 		logger := gologTest.NewNullLogger()
 
 		o := newPlacedOrder(t)
@@ -125,7 +283,7 @@ func TestService_MarkAsShipped(t *testing.T) {
 		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
 		repo.EXPECT().Add(ctx, gomock.Any()).Return(order.ErrNotAdded)
 
-		svc := NewService(repo, logger)
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
 
 		o, err := svc.MarkAsShipped(ctx, o.ID)
 		if !errors.Is(err, ErrNotPlaced) && !errors.Is(err, order.ErrNotAdded) {
@@ -137,6 +295,34 @@ func TestService_MarkAsShipped(t *testing.T) {
 		}
 	})
 
+	t.Run("modified concurrently", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		o := newPlacedOrder(t)
+
+		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
+		repo.EXPECT().Add(ctx, gomock.Any()).Return(fmt.Errorf("%w: %w", order.ErrNotAdded, order.ErrConcurrentModification))
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+
+		o, err := svc.MarkAsShipped(ctx, o.ID)
+		if !errors.Is(err, ErrNotMarkedAsShipped) || !errors.Is(err, ErrConflict) || !errors.Is(err, order.ErrConcurrentModification) {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
 	t.Run("marked as shipped", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		t.Cleanup(func() {
@@ -145,14 +331,17 @@ func TestService_MarkAsShipped(t *testing.T) {
 
 		ctx := context.Background()
 		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
 		logger := gologTest.NewNullLogger()
 
 		o := newPlacedOrder(t)
 
 		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
 		repo.EXPECT().Add(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
 
-		svc := NewService(repo, logger)
+		clock := newClock(t)
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger, WithClock(clock))
 
 		o, err := svc.MarkAsShipped(ctx, o.ID)
 		if err != nil {
@@ -165,10 +354,135 @@ func TestService_MarkAsShipped(t *testing.T) {
 			t.Errorf("want: %s", order.Shipped)
 		}
 
-		if time.Until(o.ShippedAt) > time.Second {
+		if !o.ShippedAt.Equal(clock.Now()) {
 			t.Errorf("could not match shipped at time: %s", o.PlacedAt)
 		}
 	})
+
+	t.Run("within transaction", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		txCtx := context.WithValue(ctx, txKey{}, "tx")
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		transactor := NewMockTransactor(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		o := newPlacedOrder(t)
+
+		gomock.InOrder(
+			transactor.EXPECT().WithinTransaction(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, fn func(context.Context) error) error {
+				return fn(txCtx)
+			}),
+			publisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil),
+		)
+		repo.EXPECT().Find(txCtx, o.ID).Return(o, nil)
+		repo.EXPECT().Add(txCtx, gomock.Any()).Return(nil)
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger, WithTransactor(transactor))
+
+		if _, err := svc.MarkAsShipped(ctx, o.ID); err != nil {
+			t.Fatalf("could match error: %s", err)
+		}
+	})
+
+	t.Run("not committed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		transactor := NewMockTransactor(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		o := newPlacedOrder(t)
+
+		transactor.EXPECT().WithinTransaction(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			if err := fn(ctx); err != nil {
+				return err
+			}
+			return ErrNotCommitted
+		})
+		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
+		repo.EXPECT().Add(ctx, gomock.Any()).Return(nil)
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger, WithTransactor(transactor))
+
+		o, err := svc.MarkAsShipped(ctx, o.ID)
+		if !errors.Is(err, ErrNotMarkedAsShipped) || !errors.Is(err, ErrNotCommitted) {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
+	t.Run("locked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		locker := order.NewMockLocker(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		id := newID(t)
+		locker.EXPECT().GetForUpdate(ctx, id, order.LockNoWait).Return(nil, order.ErrLocked)
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger, WithLocker(locker, order.LockNoWait))
+
+		o, err := svc.MarkAsShipped(ctx, id)
+		if !errors.Is(err, ErrNotMarkedAsShipped) || !errors.Is(err, ErrConflict) || !errors.Is(err, order.ErrLocked) {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
+	t.Run("marked as shipped once locked", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		locker := order.NewMockLocker(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		o := newPlacedOrder(t)
+
+		locker.EXPECT().GetForUpdate(ctx, o.ID, order.LockWait).Return(o, nil)
+		repo.EXPECT().Add(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger, WithLocker(locker, order.LockWait))
+
+		o, err := svc.MarkAsShipped(ctx, o.ID)
+		if err != nil {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if o.Status != order.Shipped {
+			t.Errorf("could not match status")
+			t.Errorf("got: %s", o.Status)
+			t.Errorf("want: %s", order.Shipped)
+		}
+	})
 }
 
 func TestService_MarkAsDelivered(t *testing.T) {
@@ -180,9 +494,10 @@ func TestService_MarkAsDelivered(t *testing.T) {
 
 		ctx := context.Background()
 		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
 		logger := gologTest.NewNullLogger()
 
-		svc := NewService(repo, logger)
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
 		id := newID(t)
 
 		repo.EXPECT().Find(ctx, id).Return(nil, order.ErrNotFound)
@@ -205,6 +520,7 @@ func TestService_MarkAsDelivered(t *testing.T) {
 
 		ctx := context.Background()
 		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
 		logger := gologTest.NewNullLogger()
 
 		o := newShippedOrder(t)
@@ -212,7 +528,7 @@ func TestService_MarkAsDelivered(t *testing.T) {
 		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
 		repo.EXPECT().Add(ctx, gomock.Any()).Return(order.ErrNotAdded)
 
-		svc := NewService(repo, logger)
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
 
 		o, err := svc.MarkAsDelivered(ctx, o.ID)
 		if !errors.Is(err, ErrNotMarkedAsDelivered) && !errors.Is(err, order.ErrNotAdded) {
@@ -232,14 +548,17 @@ func TestService_MarkAsDelivered(t *testing.T) {
 
 		ctx := context.Background()
 		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
 		logger := gologTest.NewNullLogger()
 
 		o := newShippedOrder(t)
 
 		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
 		repo.EXPECT().Add(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
 
-		svc := NewService(repo, logger)
+		clock := newClock(t)
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger, WithClock(clock))
 
 		o, err := svc.MarkAsDelivered(ctx, o.ID)
 		if err != nil {
@@ -252,25 +571,308 @@ func TestService_MarkAsDelivered(t *testing.T) {
 			t.Errorf("want: %s", order.Delivered)
 		}
 
-		if time.Until(o.DeliveredAt) > time.Second {
+		if !o.DeliveredAt.Equal(clock.Now()) {
 			t.Errorf("could not match delivered at time: %s", o.DeliveredAt)
 		}
 	})
 }
 
+func TestService_Cancel(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+		id := newID(t)
+
+		repo.EXPECT().Find(ctx, id).Return(nil, order.ErrNotFound)
+
+		o, err := svc.Cancel(ctx, id, order.CustomerRequest)
+		if !errors.Is(err, ErrNotCancelled) || !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
+	t.Run("not cancelled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		o := newShippedOrder(t)
+		if err := o.MarkAsDelivered(newClock(t)); err != nil {
+			t.Fatalf("could not mark order as delivered: %s", err)
+		}
+
+		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+
+		o, err := svc.Cancel(ctx, o.ID, order.CustomerRequest)
+		if !errors.Is(err, ErrNotCancelled) || !errors.Is(err, order.ErrNotCancelled) {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
+	t.Run("not added", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		o := newPlacedOrder(t)
+
+		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
+		repo.EXPECT().Add(ctx, gomock.Any()).Return(order.ErrNotAdded)
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+
+		o, err := svc.Cancel(ctx, o.ID, order.CustomerRequest)
+		if !errors.Is(err, ErrNotCancelled) || !errors.Is(err, order.ErrNotAdded) {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		o := newPlacedOrder(t)
+
+		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
+		repo.EXPECT().Add(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+
+		clock := newClock(t)
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger, WithClock(clock))
+
+		o, err := svc.Cancel(ctx, o.ID, order.PaymentFailed)
+		if err != nil {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if o.Status != order.Cancelled {
+			t.Errorf("could not match status")
+			t.Errorf("got: %s", o.Status)
+			t.Errorf("want: %s", order.Cancelled)
+		}
+
+		if o.CancellationReason != order.PaymentFailed {
+			t.Errorf("could not match cancellation reason")
+			t.Errorf("got: %s", o.CancellationReason)
+			t.Errorf("want: %s", order.PaymentFailed)
+		}
+
+		if !o.CancelledAt.Equal(clock.Now()) {
+			t.Errorf("could not match cancelled at time: %s", o.CancelledAt)
+		}
+	})
+}
+
+func TestService_ChangeShippingAddress(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+		id := newID(t)
+
+		repo.EXPECT().Find(ctx, id).Return(nil, order.ErrNotFound)
+
+		o, err := svc.ChangeShippingAddress(ctx, id, newAddress(t))
+		if !errors.Is(err, ErrShippingAddressNotChanged) || !errors.Is(err, order.ErrNotFound) {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
+	t.Run("not changed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		o := newShippedOrder(t)
+
+		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+
+		o, err := svc.ChangeShippingAddress(ctx, o.ID, newAddress(t))
+		if !errors.Is(err, ErrShippingAddressNotChanged) || !errors.Is(err, order.ErrShippingAddressNotChanged) {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		o := newPlacedOrder(t)
+		address := newAddress(t)
+
+		repo.EXPECT().Find(ctx, o.ID).Return(o, nil)
+		repo.EXPECT().Add(ctx, gomock.Any()).Return(nil)
+		publisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil)
+
+		svc := NewService(repo, order.NewMockLister(ctrl), newNumberAllocator(t), publisher, logger)
+
+		o, err := svc.ChangeShippingAddress(ctx, o.ID, address)
+		if err != nil {
+			t.Fatalf("could match error: %s", err)
+		}
+
+		if !o.ShippingAddress.Equal(address) {
+			t.Errorf("could not match shipping address")
+			t.Errorf("got: %s", o.ShippingAddress)
+			t.Errorf("want: %s", address)
+		}
+	})
+}
+
+func TestService_List(t *testing.T) {
+	t.Run("not listed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		lister := order.NewMockLister(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		f := order.Filter{Limit: order.MaxListLimit + 1}
+		lister.EXPECT().List(ctx, f, order.Cursor{}).Return(order.Page{}, order.ErrFilterNotValid)
+
+		svc := NewService(repo, lister, newNumberAllocator(t), publisher, logger)
+
+		p, err := svc.List(ctx, f, order.Cursor{})
+		if !errors.Is(err, ErrNotListed) || !errors.Is(err, order.ErrFilterNotValid) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		if p.Orders != nil {
+			t.Fatalf("could not match empty page: %v", p)
+		}
+	})
+
+	t.Run("listed", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		t.Cleanup(func() {
+			ctrl.Finish()
+		})
+
+		ctx := context.Background()
+		repo := order.NewMockRepo(ctrl)
+		lister := order.NewMockLister(ctrl)
+		publisher := NewMockPublisher(ctrl)
+		logger := gologTest.NewNullLogger()
+
+		o := newPlacedOrder(t)
+		f := order.Filter{PlacedBy: o.PlacedBy, Statuses: []order.Status{order.Placed}}
+		c := order.Cursor{PlacedAt: o.PlacedAt.Add(time.Hour), ID: newID(t)}
+		want := order.Page{Orders: []*order.Order{o}, Previous: order.CursorOf(o, order.Backward)}
+		lister.EXPECT().List(ctx, f, c).Return(want, nil)
+
+		svc := NewService(repo, lister, newNumberAllocator(t), publisher, logger)
+
+		p, err := svc.List(ctx, f, c)
+		if err != nil {
+			t.Fatalf("could not list orders: %s", err)
+		}
+
+		if len(p.Orders) != 1 || p.Orders[0] != o || p.Previous != want.Previous || !p.Next.IsZero() {
+			t.Fatalf("could not match page: %v", p)
+		}
+	})
+}
+
+type txKey struct{}
+
 func newPlacedOrder(t *testing.T) *order.Order {
 	t.Helper()
 
-	return order.Place(newOrderNumber(t), newUserID(t))
+	o, err := order.Place(newClock(t), newID(t), newOrderNumber(t), newUserID(t), newItems(t))
+	if err != nil {
+		t.Fatalf("could not place order: %s", err)
+	}
+	_ = o.PullEvents()
+
+	return o
 }
 
 func newShippedOrder(t *testing.T) *order.Order {
 	t.Helper()
 
-	o := order.Place(newOrderNumber(t), newUserID(t))
-	if err := o.MarkAsShipped(); err != nil {
+	o := newPlacedOrder(t)
+	if err := o.MarkAsShipped(newClock(t)); err != nil {
 		t.Fatalf("could not mark order as shipped: %s", err)
 	}
+	_ = o.PullEvents()
 
 	return o
 }
@@ -292,8 +894,46 @@ func newUserID(t *testing.T) order.UserID {
 	return id
 }
 
+func newItems(t *testing.T) []order.LineItem {
+	t.Helper()
+
+	return []order.LineItem{
+		{SKU: "SKU-1", Quantity: 2, UnitPrice: order.NewMoney(1050, order.EUR)},
+		{SKU: "SKU-2", Quantity: 1, UnitPrice: order.NewMoney(399, order.EUR)},
+	}
+}
+
 func newOrderNumber(t *testing.T) order.Number {
 	t.Helper()
 
 	return order.GenerateNumber()
 }
+
+func newAddress(t *testing.T) order.Address {
+	t.Helper()
+
+	a, err := order.ParseAddress("Jane Doe", []string{"Main Street 1"}, "London", "SW1A 1AA", "GB")
+	if err != nil {
+		t.Fatalf("could not parse address: %s", err)
+	}
+
+	return a
+}
+
+func newClock(t *testing.T) *order.FakeClock {
+	t.Helper()
+
+	return order.NewFakeClock(time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC))
+}
+
+func newTenant(t *testing.T) order.Tenant {
+	t.Helper()
+
+	return "web"
+}
+
+func newNumberAllocator(t *testing.T) order.NumberAllocator {
+	t.Helper()
+
+	return order.NewMemoryNumberAllocator(order.NumberFormats{newTenant(t): order.DefaultNumberFormat})
+}
@@ -0,0 +1,25 @@
+package internal
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotCommitted is returned by a Transactor when the transaction could not be started or committed
+var ErrNotCommitted = errors.New("transaction could not be committed")
+
+// Transactor represents the unit of work a use case runs in
+// The repositories called by fn with the given context take part in the same transaction,
+// which is committed when fn succeeds and rolled back otherwise, the error of fn being returned as is
+// A call within a running transaction joins it, so that use cases can be composed
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// noTransaction represents a Transactor running fn as is, used when the storage does not support transactions
+type noTransaction struct{}
+
+// WithinTransaction calls fn with the given context
+func (noTransaction) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,111 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	// ErrSKUNotParsed represents an error returned by a sku value type (aka value objects)
+	ErrSKUNotParsed = errors.New("could not parse sku")
+	// ErrLineItemNotValid represents an error returned by a line item value type (aka value objects)
+	ErrLineItemNotValid = errors.New("could not create line item")
+)
+
+var skuRegexp = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]{0,63}$`)
+
+// SKU represents the stock keeping unit identifying a product
+type SKU string
+
+// ParseSKU returns a SKU or an error if the given string is not a valid SKU
+func ParseSKU(s string) (SKU, error) {
+	if !skuRegexp.MatchString(s) {
+		return "", ErrSKUNotParsed
+	}
+
+	return SKU(s), nil
+}
+
+// IsZero reports whether s represents the zero SKU
+func (s SKU) IsZero() bool {
+	return s == ""
+}
+
+// String returns the SKU as string
+func (s SKU) String() string {
+	return string(s)
+}
+
+// LineItem represents a product ordered in a given quantity
+type LineItem struct {
+	SKU       SKU
+	Quantity  int
+	UnitPrice Money
+}
+
+// NewLineItem returns a LineItem or an error if it violates the domain invariants
+func NewLineItem(sku SKU, quantity int, unitPrice Money) (LineItem, error) {
+	switch {
+	case sku.IsZero():
+		return LineItem{}, fmt.Errorf("%w: missing sku", ErrLineItemNotValid)
+	case quantity <= 0:
+		return LineItem{}, fmt.Errorf("%w: quantity must be positive", ErrLineItemNotValid)
+	case unitPrice.Currency().IsZero():
+		return LineItem{}, fmt.Errorf("%w: missing unit price currency", ErrLineItemNotValid)
+	case unitPrice.IsNegative():
+		return LineItem{}, fmt.Errorf("%w: unit price must not be negative", ErrLineItemNotValid)
+	}
+
+	return LineItem{SKU: sku, Quantity: quantity, UnitPrice: unitPrice}, nil
+}
+
+// Total returns the price of the line item for its whole quantity
+func (i LineItem) Total() (Money, error) {
+	return i.UnitPrice.Multiply(int64(i.Quantity))
+}
+
+// validateItems checks the invariants of the items of an order
+func validateItems(items []LineItem) error {
+	if len(items) == 0 {
+		return errors.New("no items")
+	}
+
+	seen := make(map[SKU]bool, len(items))
+	for _, i := range items {
+		if _, err := NewLineItem(i.SKU, i.Quantity, i.UnitPrice); err != nil {
+			return err
+		}
+
+		if seen[i.SKU] {
+			return fmt.Errorf("duplicated sku: %s", i.SKU)
+		}
+		seen[i.SKU] = true
+	}
+
+	_, err := totalOf(items)
+	return err
+}
+
+// totalOf sums up the price of the given items
+// It fails when the items are priced in different currencies
+func totalOf(items []LineItem) (Money, error) {
+	var total Money
+	for n, i := range items {
+		t, err := i.Total()
+		if err != nil {
+			return Money{}, err
+		}
+
+		if n == 0 {
+			total = t
+			continue
+		}
+
+		if total, err = total.Add(t); err != nil {
+			return Money{}, err
+		}
+	}
+
+	return total, nil
+}
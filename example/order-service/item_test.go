@@ -0,0 +1,68 @@
+package order_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/organization/order-service"
+)
+
+func TestParseSKU(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		sku, err := ParseSKU("TSHIRT-RED_XL")
+		if err != nil {
+			t.Fatalf("could not parse sku: %s", err)
+		}
+
+		if sku.String() != "TSHIRT-RED_XL" {
+			t.Fatalf("could not match sku as its raw format: %s", sku)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, raw := range []string{"", "lowercase", "-LEADING-DASH", "WITH SPACE"} {
+			sku, err := ParseSKU(raw)
+			if !errors.Is(err, ErrSKUNotParsed) {
+				t.Fatalf("could not match error for %q: %s", raw, err)
+			}
+
+			if !sku.IsZero() {
+				t.Fatalf("could not match an empty sku: %s", sku)
+			}
+		}
+	})
+}
+
+func TestNewLineItem(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		i, err := NewLineItem("SKU-1", 3, NewMoney(250, EUR))
+		if err != nil {
+			t.Fatalf("could not create line item: %s", err)
+		}
+
+		total, err := i.Total()
+		if err != nil {
+			t.Fatalf("could not compute line item total: %s", err)
+		}
+
+		if want := NewMoney(750, EUR); total != want {
+			t.Error("could not match line item total")
+			t.Errorf("got: %s", total)
+			t.Errorf("want: %s", want)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := NewLineItem("", 1, NewMoney(250, EUR)); !errors.Is(err, ErrLineItemNotValid) {
+			t.Errorf("could not match missing sku error: %s", err)
+		}
+
+		if _, err := NewLineItem("SKU-1", 0, NewMoney(250, EUR)); !errors.Is(err, ErrLineItemNotValid) {
+			t.Errorf("could not match quantity error: %s", err)
+		}
+
+		if _, err := NewLineItem("SKU-1", 1, NewMoney(-1, EUR)); !errors.Is(err, ErrLineItemNotValid) {
+			t.Errorf("could not match unit price error: %s", err)
+		}
+	})
+}
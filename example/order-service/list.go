@@ -0,0 +1,120 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNotListed is returned when the orders could not be listed
+	ErrNotListed = errors.New("could not list orders")
+	// ErrFilterNotValid represents an error returned by a filter value type (aka value objects)
+	ErrFilterNotValid = errors.New("could not use filter")
+)
+
+const (
+	// DefaultListLimit is the number of orders listed when the filter has no limit
+	DefaultListLimit = 20
+	// MaxListLimit is the maximum number of orders listed at once
+	MaxListLimit = 100
+)
+
+// Lister represents the layer to query orders from the storage
+// Orders are listed from the most recently placed, ties being broken by descending ID,
+// one page at a time starting from the cursor (see NewPage)
+type Lister interface {
+	List(ctx context.Context, filter Filter, cursor Cursor) (Page, error)
+}
+
+// TimeRange represents a time interval including From and excluding To
+// A zero bound leaves the interval open on that side
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// IsZero reports whether r represents the zero TimeRange, matching any time
+func (r TimeRange) IsZero() bool {
+	return r.From.IsZero() && r.To.IsZero()
+}
+
+// Contains reports whether t is in the range
+// A zero time, as held by orders not shipped or delivered yet, is only contained by the zero TimeRange
+func (r TimeRange) Contains(t time.Time) bool {
+	if r.IsZero() {
+		return true
+	}
+
+	if t.IsZero() {
+		return false
+	}
+
+	return (r.From.IsZero() || !t.Before(r.From)) && (r.To.IsZero() || t.Before(r.To))
+}
+
+// Filter represents the criteria orders are listed by
+// Zero fields match any order
+type Filter struct {
+	PlacedBy    UserID
+	Statuses    []Status
+	PlacedAt    TimeRange
+	ShippedAt   TimeRange
+	DeliveredAt TimeRange
+	// Limit is the maximum number of orders listed per page, DefaultListLimit when zero
+	Limit int
+}
+
+// Validate returns ErrFilterNotValid when the filter cannot be used to list orders
+func (f Filter) Validate() error {
+	if f.Limit < 0 || f.Limit > MaxListLimit {
+		return fmt.Errorf("%w: limit %d is not between 0 and %d", ErrFilterNotValid, f.Limit, MaxListLimit)
+	}
+
+	for _, s := range f.Statuses {
+		if !isKnownStatus(s) {
+			return fmt.Errorf("%w: unknown status %q", ErrFilterNotValid, s)
+		}
+	}
+
+	for name, r := range map[string]TimeRange{"placed at": f.PlacedAt, "shipped at": f.ShippedAt, "delivered at": f.DeliveredAt} {
+		if !r.From.IsZero() && !r.To.IsZero() && !r.From.Before(r.To) {
+			return fmt.Errorf("%w: %s range ends before it starts", ErrFilterNotValid, name)
+		}
+	}
+
+	return nil
+}
+
+// Size returns the number of orders to list
+func (f Filter) Size() int {
+	if f.Limit == 0 {
+		return DefaultListLimit
+	}
+
+	return f.Limit
+}
+
+// Matches reports whether the order matches the filter
+func (f Filter) Matches(o *Order) bool {
+	if !f.PlacedBy.IsZero() && o.PlacedBy != f.PlacedBy {
+		return false
+	}
+
+	if len(f.Statuses) > 0 && !containsStatus(f.Statuses, o.Status) {
+		return false
+	}
+
+	return f.PlacedAt.Contains(o.PlacedAt) && f.ShippedAt.Contains(o.ShippedAt) && f.DeliveredAt.Contains(o.DeliveredAt)
+}
+
+func containsStatus(ss []Status, s Status) bool {
+	for _, candidate := range ss {
+		if candidate == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,94 @@
+package order_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/organization/order-service"
+)
+
+func TestTimeRange_Contains(t *testing.T) {
+	at := time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		r    TimeRange
+		t    time.Time
+		want bool
+	}{
+		"zero range":           {r: TimeRange{}, t: at, want: true},
+		"zero range zero time": {r: TimeRange{}, t: time.Time{}, want: true},
+		"zero time":            {r: TimeRange{To: at}, t: time.Time{}, want: false},
+		"from included":        {r: TimeRange{From: at}, t: at, want: true},
+		"to excluded":          {r: TimeRange{To: at}, t: at, want: false},
+		"before":               {r: TimeRange{From: at, To: at.Add(time.Hour)}, t: at.Add(-time.Second), want: false},
+		"within":               {r: TimeRange{From: at, To: at.Add(time.Hour)}, t: at.Add(time.Minute), want: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.r.Contains(tt.t); got != tt.want {
+				t.Errorf("could not match contained time: got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilter_Validate(t *testing.T) {
+	at := time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC)
+
+	t.Run("valid", func(t *testing.T) {
+		f := Filter{Statuses: []Status{Placed, Shipped}, PlacedAt: TimeRange{From: at, To: at.Add(time.Hour)}, Limit: MaxListLimit}
+		if err := f.Validate(); err != nil {
+			t.Fatalf("could not validate filter: %s", err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := map[string]Filter{
+			"negative limit": {Limit: -1},
+			"limit too high": {Limit: MaxListLimit + 1},
+			"unknown status": {Statuses: []Status{"lost"}},
+			"inverted range": {ShippedAt: TimeRange{From: at, To: at.Add(-time.Hour)}},
+		}
+
+		for name, f := range tests {
+			t.Run(name, func(t *testing.T) {
+				if err := f.Validate(); !errors.Is(err, ErrFilterNotValid) {
+					t.Fatalf("could not match error: %s", err)
+				}
+			})
+		}
+	})
+}
+
+func TestFilter_Matches(t *testing.T) {
+	o := placeHelper(t)
+	clock := clockHelper(t)
+
+	tests := map[string]struct {
+		f    Filter
+		want bool
+	}{
+		"zero filter":     {f: Filter{}, want: true},
+		"placed by":       {f: Filter{PlacedBy: o.PlacedBy}, want: true},
+		"placed by other": {f: Filter{PlacedBy: userIDHelper(t)}, want: false},
+		"status":          {f: Filter{Statuses: []Status{Shipped, Placed}}, want: true},
+		"other status":    {f: Filter{Statuses: []Status{Shipped}}, want: false},
+		"placed at":       {f: Filter{PlacedAt: TimeRange{From: clock.Now()}}, want: true},
+		"placed before":   {f: Filter{PlacedAt: TimeRange{To: clock.Now()}}, want: false},
+		"not shipped yet": {f: Filter{ShippedAt: TimeRange{To: clock.Now().Add(time.Hour)}}, want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.f.Matches(o); got != tt.want {
+				t.Errorf("could not match filter: got %t, want %t", got, tt.want)
+			}
+		})
+	}
+
+	if (Filter{}).Size() != DefaultListLimit {
+		t.Errorf("could not match default size: %d", (Filter{}).Size())
+	}
+}
@@ -0,0 +1,60 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrLocked is returned when an order could not be locked since someone else holds its lock
+	ErrLocked = errors.New("order is locked")
+	// ErrLockModeNotParsed represents an error returned by a lock mode value type (aka value objects)
+	ErrLockModeNotParsed = errors.New("could not parse lock mode")
+)
+
+// LockMode represents how an order locked by someone else is waited for
+type LockMode int
+
+const (
+	// LockWait waits until the lock of the order is released
+	LockWait LockMode = iota
+	// LockNoWait fails right away with ErrLocked
+	LockNoWait
+	// LockSkipLocked skips the locked order, failing with ErrLocked as well, so that workers move on to other orders
+	LockSkipLocked
+)
+
+// ParseLockMode returns a LockMode or an error if the given string is not a known lock mode (wait, nowait or skip_locked)
+func ParseLockMode(s string) (LockMode, error) {
+	switch s {
+	case "wait":
+		return LockWait, nil
+	case "nowait":
+		return LockNoWait, nil
+	case "skip_locked":
+		return LockSkipLocked, nil
+	default:
+		return 0, fmt.Errorf("%w: %q", ErrLockModeNotParsed, s)
+	}
+}
+
+// String returns the LockMode as string
+func (m LockMode) String() string {
+	switch m {
+	case LockWait:
+		return "wait"
+	case LockNoWait:
+		return "nowait"
+	case LockSkipLocked:
+		return "skip_locked"
+	default:
+		return fmt.Sprintf("LockMode(%d)", int(m))
+	}
+}
+
+// Locker represents the layer to read orders from the storage while locking them against concurrent changes
+// The lock is held until the end of the transaction carried by the context, so it must be called within one
+type Locker interface {
+	GetForUpdate(ctx context.Context, id ID, mode LockMode) (*Order, error)
+}
@@ -0,0 +1,31 @@
+package order_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/organization/order-service"
+)
+
+func TestParseLockMode(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, raw := range []string{"wait", "nowait", "skip_locked"} {
+			m, err := ParseLockMode(raw)
+			if err != nil {
+				t.Fatalf("could not parse lock mode: %s", err)
+			}
+
+			if m.String() != raw {
+				t.Error("could not match lock mode as its raw format")
+				t.Errorf("got: %s", m)
+				t.Fatalf("want: %s", raw)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := ParseLockMode("forever"); !errors.Is(err, ErrLockModeNotParsed) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+}
@@ -0,0 +1,342 @@
+package order
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrCurrencyNotParsed represents an error returned by a currency value type (aka value objects)
+	ErrCurrencyNotParsed = errors.New("could not parse currency")
+	// ErrMoneyNotParsed represents an error returned by a money value type (aka value objects)
+	ErrMoneyNotParsed = errors.New("could not parse money")
+	// ErrCurrencyMismatch is returned when operating on money of different currencies
+	ErrCurrencyMismatch = errors.New("could not operate on money of different currencies")
+	// ErrMoneyOverflow is returned when an operation exceeds the amount money can hold
+	ErrMoneyOverflow = errors.New("could not operate on money: overflow")
+)
+
+const (
+	EUR Currency = "EUR"
+	USD Currency = "USD"
+	GBP Currency = "GBP"
+	CHF Currency = "CHF"
+	JPY Currency = "JPY"
+)
+
+// exponents maps the supported ISO 4217 currencies to the number of their minor unit digits
+var exponents = map[Currency]int{
+	"AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, CHF: 2, "CNY": 2, "CZK": 2, "DKK": 2,
+	EUR: 2, GBP: 2, "HKD": 2, "HUF": 2, "INR": 2, JPY: 0, "KRW": 0, "KWD": 3,
+	"MXN": 2, "NOK": 2, "NZD": 2, "PLN": 2, "SEK": 2, "SGD": 2, "TND": 3, USD: 2,
+}
+
+// Currency represents an ISO 4217 currency code
+type Currency string
+
+// ParseCurrency returns a Currency or an error if the given string is not a supported ISO 4217 code
+func ParseCurrency(s string) (Currency, error) {
+	c := Currency(s)
+	if _, ok := exponents[c]; !ok {
+		return "", ErrCurrencyNotParsed
+	}
+
+	return c, nil
+}
+
+// Exponent returns the number of digits of the minor unit of the currency (e.g. 2 for EUR cents)
+func (c Currency) Exponent() int {
+	return exponents[c]
+}
+
+// IsZero reports whether c represents the zero Currency
+func (c Currency) IsZero() bool {
+	return c == ""
+}
+
+// String returns the Currency as string
+func (c Currency) String() string {
+	return string(c)
+}
+
+// Scan implements sql.Scanner
+func (c *Currency) Scan(src any) error {
+	s, err := scanString(src)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCurrencyNotParsed, err)
+	}
+
+	parsed, err := ParseCurrency(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+
+	*c = parsed
+	return nil
+}
+
+// Value implements driver.Valuer
+func (c Currency) Value() (driver.Value, error) {
+	return c.String(), nil
+}
+
+// Rounding represents how an amount is rounded to the closest minor unit
+type Rounding int
+
+const (
+	// HalfEven rounds to the nearest minor unit, ties to the even one (aka banker's rounding)
+	HalfEven Rounding = iota
+	// HalfUp rounds to the nearest minor unit, ties away from zero
+	HalfUp
+	// Down rounds towards zero
+	Down
+)
+
+// Money represents an amount of money in a currency
+// It is backed by integer minor units (e.g. cents) so that arithmetic is exact
+// Repositories store it as separate amount and currency columns, using Amount and Currency, the latter being a sql.Scanner
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// NewMoney returns a Money of the given minor units in the given currency
+func NewMoney(minorUnits int64, c Currency) Money {
+	return Money{amount: minorUnits, currency: c}
+}
+
+// ParseMoney returns a Money or an error if the given string is not formatted as "<amount> <currency>" (e.g. "12.30 EUR")
+// The amount must not have more decimals than the currency minor unit, as it would require rounding,
+// nor a decimal point without decimals (e.g. "12. EUR")
+func ParseMoney(s string) (Money, error) {
+	rawAmount, rawCurrency, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return Money{}, ErrMoneyNotParsed
+	}
+
+	c, err := ParseCurrency(rawCurrency)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %w", ErrMoneyNotParsed, err)
+	}
+
+	sign, abs := "", rawAmount
+	if strings.HasPrefix(rawAmount, "-") {
+		sign, abs = "-", rawAmount[1:]
+	}
+
+	units, decimals, point := strings.Cut(abs, ".")
+	if units == "" || (point && decimals == "") || len(decimals) > c.Exponent() || strings.ContainsAny(units+decimals, "+-") {
+		return Money{}, ErrMoneyNotParsed
+	}
+
+	// the sign is parsed along with the digits, so that the lowest amount does not overflow once negated
+	digits := sign + units + decimals + strings.Repeat("0", c.Exponent()-len(decimals))
+	amount, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Money{}, ErrMoneyNotParsed
+	}
+
+	return NewMoney(amount, c), nil
+}
+
+// Amount returns the amount in minor units
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+// Currency returns the currency of the money
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// IsZero reports whether m represents the zero Money
+func (m Money) IsZero() bool {
+	return m == Money{}
+}
+
+// IsNegative reports whether the amount is lower than zero
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Add returns the sum of the two amounts
+// It returns ErrCurrencyMismatch when the currencies differ
+func (m Money) Add(o Money) (Money, error) {
+	if m.currency != o.currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency, o.currency)
+	}
+
+	sum := m.amount + o.amount
+	if (o.amount > 0 && sum < m.amount) || (o.amount < 0 && sum > m.amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return NewMoney(sum, m.currency), nil
+}
+
+// Sub returns the difference of the two amounts
+// It returns ErrCurrencyMismatch when the currencies differ
+func (m Money) Sub(o Money) (Money, error) {
+	if o.amount == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return m.Add(NewMoney(-o.amount, o.currency))
+}
+
+// Multiply returns the amount multiplied by the given integer (e.g. a quantity)
+func (m Money) Multiply(n int64) (Money, error) {
+	if m.amount == 0 || n == 0 {
+		return NewMoney(0, m.currency), nil
+	}
+
+	p := m.amount * n
+	if p/n != m.amount || (m.amount == -1 && n == math.MinInt64) || (n == -1 && m.amount == math.MinInt64) {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return NewMoney(p, m.currency), nil
+}
+
+// Scale returns the amount multiplied by the fraction num/den (e.g. 22/100 for a 22% tax)
+// The result is rounded to the closest minor unit using the given rounding
+func (m Money) Scale(num, den int64, r Rounding) (Money, error) {
+	if den == 0 {
+		return Money{}, errors.New("could not scale money: zero denominator")
+	}
+
+	q, rem := new(big.Int).QuoRem(
+		new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(num)),
+		big.NewInt(den),
+		new(big.Int),
+	)
+
+	if rem.Sign() != 0 {
+		// the sign of the exact result, as QuoRem truncates towards zero
+		sign := rem.Sign() * big.NewInt(den).Sign()
+		twice := new(big.Int).Abs(new(big.Int).Mul(rem, big.NewInt(2)))
+		cmp := twice.Cmp(new(big.Int).Abs(big.NewInt(den)))
+
+		var up bool
+		switch r {
+		case HalfUp:
+			up = cmp >= 0
+		case HalfEven:
+			up = cmp > 0 || (cmp == 0 && q.Bit(0) == 1)
+		case Down:
+			up = false
+		}
+
+		if up {
+			q.Add(q, big.NewInt(int64(sign)))
+		}
+	}
+
+	if !q.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+
+	return NewMoney(q.Int64(), m.currency), nil
+}
+
+// Allocate splits the amount according to the given ratios without losing any minor unit
+// The remainder is distributed one minor unit at a time, starting from the first share
+func (m Money) Allocate(ratios ...int) ([]Money, error) {
+	var total int64
+	for _, r := range ratios {
+		if r < 0 {
+			return nil, errors.New("could not allocate money: negative ratio")
+		}
+		total += int64(r)
+	}
+
+	if total == 0 {
+		return nil, errors.New("could not allocate money: no ratio")
+	}
+
+	shares := make([]Money, len(ratios))
+	remainder := m.amount
+	for i, r := range ratios {
+		share, err := m.Scale(int64(r), total, Down)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = share
+		remainder -= share.amount
+	}
+
+	unit := int64(1)
+	if remainder < 0 {
+		unit = -1
+	}
+
+	for i := 0; remainder != 0; i = (i + 1) % len(shares) {
+		if ratios[i] == 0 {
+			continue
+		}
+		shares[i].amount += unit
+		remainder -= unit
+	}
+
+	return shares, nil
+}
+
+// String returns the Money formatted as "<amount> <currency>" (e.g. "12.30 EUR")
+func (m Money) String() string {
+	exp := m.currency.Exponent()
+	abs := strconv.FormatUint(absUint(m.amount), 10)
+	if len(abs) <= exp {
+		abs = strings.Repeat("0", exp-len(abs)+1) + abs
+	}
+
+	sign := ""
+	if m.amount < 0 {
+		sign = "-"
+	}
+
+	if exp == 0 {
+		return fmt.Sprintf("%s%s %s", sign, abs, m.currency)
+	}
+
+	return fmt.Sprintf("%s%s.%s %s", sign, abs[:len(abs)-exp], abs[len(abs)-exp:], m.currency)
+}
+
+// MarshalText encodes the Money as text
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes the Money from text
+func (m *Money) UnmarshalText(b []byte) error {
+	parsed, err := ParseMoney(string(b))
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+func scanString(src any) (string, error) {
+	switch v := src.(type) {
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", src)
+	}
+}
+
+func absUint(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+
+	return uint64(n)
+}
@@ -0,0 +1,214 @@
+package order_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	. "github.com/organization/order-service"
+)
+
+func TestParseCurrency(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		c, err := ParseCurrency("JPY")
+		if err != nil {
+			t.Fatalf("could not parse currency: %s", err)
+		}
+
+		if c != JPY || c.Exponent() != 0 {
+			t.Fatalf("could not match currency: %s", c)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, raw := range []string{"", "eur", "XXX", "EURO"} {
+			if _, err := ParseCurrency(raw); !errors.Is(err, ErrCurrencyNotParsed) {
+				t.Fatalf("could not match error for %q: %s", raw, err)
+			}
+		}
+	})
+}
+
+func TestParseMoney(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		tests := []struct {
+			raw  string
+			want Money
+			str  string
+		}{
+			{raw: "12.30 EUR", want: NewMoney(1230, EUR), str: "12.30 EUR"},
+			{raw: "12.3 EUR", want: NewMoney(1230, EUR), str: "12.30 EUR"},
+			{raw: "12 EUR", want: NewMoney(1200, EUR), str: "12.00 EUR"},
+			{raw: "0.05 USD", want: NewMoney(5, USD), str: "0.05 USD"},
+			{raw: "-7.01 GBP", want: NewMoney(-701, GBP), str: "-7.01 GBP"},
+			{raw: "1500 JPY", want: NewMoney(1500, JPY), str: "1500 JPY"},
+			{raw: "1.005 KWD", want: NewMoney(1005, "KWD"), str: "1.005 KWD"},
+		}
+
+		for _, tt := range tests {
+			m, err := ParseMoney(tt.raw)
+			if err != nil {
+				t.Fatalf("could not parse money %q: %s", tt.raw, err)
+			}
+
+			if m != tt.want {
+				t.Errorf("could not match money parsed from %q", tt.raw)
+				t.Errorf("got: %#v", m)
+				t.Errorf("want: %#v", tt.want)
+			}
+
+			if m.String() != tt.str {
+				t.Errorf("could not match money as string")
+				t.Errorf("got: %s", m.String())
+				t.Errorf("want: %s", tt.str)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, raw := range []string{"", "12.30", "EUR", "12.345 EUR", "1.5 JPY", "abc EUR", "--1 EUR", "1.-5 EUR", "12.30 XXX", "12. EUR", "-12. EUR", "1500. JPY", ". EUR"} {
+			if _, err := ParseMoney(raw); !errors.Is(err, ErrMoneyNotParsed) {
+				t.Errorf("could not match error for %q: %s", raw, err)
+			}
+		}
+	})
+}
+
+func TestMoney_Add(t *testing.T) {
+	sum, err := NewMoney(1050, EUR).Add(NewMoney(250, EUR))
+	if err != nil {
+		t.Fatalf("could not add money: %s", err)
+	}
+
+	if sum != NewMoney(1300, EUR) {
+		t.Errorf("could not match sum: %s", sum)
+	}
+
+	if _, err := NewMoney(1050, EUR).Add(NewMoney(250, USD)); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("could not match currency mismatch error: %s", err)
+	}
+
+	if _, err := NewMoney(math.MaxInt64, EUR).Add(NewMoney(1, EUR)); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("could not match overflow error: %s", err)
+	}
+
+	diff, err := NewMoney(1050, EUR).Sub(NewMoney(2000, EUR))
+	if err != nil {
+		t.Fatalf("could not subtract money: %s", err)
+	}
+
+	if diff != NewMoney(-950, EUR) {
+		t.Errorf("could not match difference: %s", diff)
+	}
+}
+
+func TestMoney_Multiply(t *testing.T) {
+	p, err := NewMoney(1999, EUR).Multiply(3)
+	if err != nil {
+		t.Fatalf("could not multiply money: %s", err)
+	}
+
+	if p != NewMoney(5997, EUR) {
+		t.Errorf("could not match product: %s", p)
+	}
+
+	if _, err := NewMoney(math.MaxInt64/2+1, EUR).Multiply(2); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("could not match overflow error: %s", err)
+	}
+}
+
+func TestMoney_Scale(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		num, den int64
+		rounding Rounding
+		want     int64
+	}{
+		{amount: 1000, num: 22, den: 100, rounding: HalfEven, want: 220},
+		{amount: 25, num: 1, den: 10, rounding: HalfEven, want: 2},
+		{amount: 35, num: 1, den: 10, rounding: HalfEven, want: 4},
+		{amount: 25, num: 1, den: 10, rounding: HalfUp, want: 3},
+		{amount: -25, num: 1, den: 10, rounding: HalfUp, want: -3},
+		{amount: -25, num: 1, den: 10, rounding: HalfEven, want: -2},
+		{amount: 29, num: 1, den: 10, rounding: Down, want: 2},
+		{amount: -29, num: 1, den: 10, rounding: Down, want: -2},
+	}
+
+	for _, tt := range tests {
+		got, err := NewMoney(tt.amount, EUR).Scale(tt.num, tt.den, tt.rounding)
+		if err != nil {
+			t.Fatalf("could not scale money: %s", err)
+		}
+
+		if got.Amount() != tt.want {
+			t.Errorf("could not match %d * %d/%d with rounding %d", tt.amount, tt.num, tt.den, tt.rounding)
+			t.Errorf("got: %d", got.Amount())
+			t.Errorf("want: %d", tt.want)
+		}
+	}
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		amount int64
+		ratios []int
+		want   []int64
+	}{
+		{amount: 100, ratios: []int{1, 1, 1}, want: []int64{34, 33, 33}},
+		{amount: 5, ratios: []int{3, 7}, want: []int64{2, 3}},
+		{amount: -100, ratios: []int{1, 1, 1}, want: []int64{-34, -33, -33}},
+		{amount: 10, ratios: []int{0, 1, 1}, want: []int64{0, 5, 5}},
+	}
+
+	for _, tt := range tests {
+		shares, err := NewMoney(tt.amount, EUR).Allocate(tt.ratios...)
+		if err != nil {
+			t.Fatalf("could not allocate money: %s", err)
+		}
+
+		var sum int64
+		for i, s := range shares {
+			sum += s.Amount()
+			if s.Amount() != tt.want[i] || s.Currency() != EUR {
+				t.Errorf("could not match share %d of %d allocated by %v: %s", i, tt.amount, tt.ratios, s)
+			}
+		}
+
+		if sum != tt.amount {
+			t.Errorf("could not match allocated sum: %d", sum)
+		}
+	}
+
+	if _, err := NewMoney(10, EUR).Allocate(0, 0); err == nil {
+		t.Errorf("could allocate money without ratios")
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	for _, c := range []Currency{EUR, JPY, "KWD"} {
+		for _, amount := range []int64{math.MinInt64, -1, 0, 1, math.MaxInt64} {
+			m := NewMoney(amount, c)
+			parsed, err := ParseMoney(m.String())
+			if err != nil {
+				t.Fatalf("could not parse money %q: %s", m, err)
+			}
+
+			if parsed != m {
+				t.Error("could not match money once formatted and parsed")
+				t.Errorf("got: %#v", parsed)
+				t.Errorf("want: %#v", m)
+			}
+		}
+	}
+}
+
+func TestCurrency_Scan(t *testing.T) {
+	var c Currency
+	if err := c.Scan("USD"); err != nil || c != USD {
+		t.Errorf("could not scan currency: %s", err)
+	}
+
+	if err := c.Scan(42); !errors.Is(err, ErrCurrencyNotParsed) {
+		t.Errorf("could not match error: %s", err)
+	}
+}
@@ -1,31 +1,259 @@
 package order
 
 import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
 	"strings"
+	"time"
+)
 
-	"github.com/google/uuid"
+var (
+	// ErrNumberNotParsed represents an error returned by a number value type (aka value objects)
+	ErrNumberNotParsed = errors.New("could not parse order number")
+	// ErrNumberChecksumMismatch is returned when the check symbol of a number does not match the rest of it
+	ErrNumberChecksumMismatch = errors.New("order number checksum mismatch")
+	// ErrNumberNotFormatted is returned when a number cannot be built with the given format
+	ErrNumberNotFormatted = errors.New("could not format order number")
 )
 
-// Number represents an order number.
-// It is a random 32 chars string
-type Number [32]byte
+const (
+	// crockford is the Crockford base32 alphabet, which excludes I, L, O and U to avoid misreadings
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	numberSeparator = "-"
+	maxNumberLength = 32
+)
+
+var numberPrefix = regexp.MustCompile(`^[A-Z]{0,8}$`)
+
+// DefaultNumberFormat is the format used by GenerateNumber and ParseNumber (e.g. ORD-230321-0004F7-9)
+var DefaultNumberFormat = NumberFormat{
+	Prefix:         "ORD",
+	DateLayout:     "060102",
+	SequenceLength: 6,
+}
+
+// NumberFormat represents a scheme of order numbers
+// A number is made of an optional prefix, an optional date, a Crockford base32 sequence and a check symbol,
+// separated by dashes (e.g. ORD-230321-0004F7-9)
+type NumberFormat struct {
+	// Prefix is made of up to 8 uppercase letters, no prefix is used when empty
+	Prefix string
+	// DateLayout is the layout of the date as accepted by time.Format, no date is used when empty
+	// It must only produce digits (e.g. 060102 or 20060102)
+	DateLayout string
+	// SequenceLength is the number of base32 symbols of the sequence
+	SequenceLength int
+}
+
+// NumberError represents an error returned when a string is not a valid Number
+// It wraps ErrNumberNotParsed and the reason the number was rejected
+type NumberError struct {
+	Input  string
+	Reason error
+}
+
+// Error returns the NumberError as string
+func (e *NumberError) Error() string {
+	return fmt.Sprintf("%s %q: %s", ErrNumberNotParsed, e.Input, e.Reason)
+}
+
+// Unwrap returns the errors wrapped by the NumberError
+func (e *NumberError) Unwrap() []error {
+	return []error{ErrNumberNotParsed, e.Reason}
+}
+
+// Number represents an order number
+// It is built from a NumberFormat, so that it can be read over the phone and validated through its check symbol
+type Number string
 
-// GenerateNumber generates a new random order Number
+// GenerateNumber generates a new order Number with DefaultNumberFormat, dated now and with a random sequence
 // It is a factory function that uses the ubiquitous language of the domain
 func GenerateNumber() Number {
-	var n [32]byte
-	for i, c := range strings.ReplaceAll(uuid.NewString(), `-`, ``) {
-		n[i] = byte(c)
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
 	}
+
+	f := DefaultNumberFormat
+	n, err := f.Format(time.Now(), binary.BigEndian.Uint64(b[:])%f.capacity())
+	if err != nil {
+		panic(err)
+	}
+
 	return n
 }
 
+// ParseNumber returns a Number with DefaultNumberFormat or a *NumberError if the given string is not valid
+func ParseNumber(s string) (Number, error) {
+	return DefaultNumberFormat.Parse(s)
+}
+
+// Format returns the Number with the given date and sequence
+// It returns ErrNumberNotFormatted when the format is not valid or the sequence does not fit in it
+func (f NumberFormat) Format(date time.Time, seq uint64) (Number, error) {
+	if err := f.validate(); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrNumberNotFormatted, err)
+	}
+
+	if seq >= f.capacity() {
+		return "", fmt.Errorf("%w: sequence %d exceeds %d symbols", ErrNumberNotFormatted, seq, f.SequenceLength)
+	}
+
+	sequence := make([]byte, f.SequenceLength)
+	for i := len(sequence) - 1; i >= 0; i-- {
+		sequence[i] = crockford[seq%32]
+		seq /= 32
+	}
+
+	var payload string
+	if f.DateLayout != "" {
+		payload = date.Format(f.DateLayout)
+	}
+	payload += string(sequence)
+
+	return Number(f.join(payload, checkSymbol(payload))), nil
+}
+
+// Parse returns a Number with the format or a *NumberError if the given string is not valid
+// Lowercase symbols and the Crockford aliases of the sequence (O for 0, I and L for 1) are accepted
+func (f NumberFormat) Parse(s string) (Number, error) {
+	if err := f.validate(); err != nil {
+		return "", &NumberError{Input: s, Reason: err}
+	}
+
+	parts := strings.Split(strings.ToUpper(strings.TrimSpace(s)), numberSeparator)
+	if len(parts) != f.segments() {
+		return "", &NumberError{Input: s, Reason: fmt.Errorf("expected %d segments", f.segments())}
+	}
+
+	if f.Prefix != "" {
+		if parts[0] != f.Prefix {
+			return "", &NumberError{Input: s, Reason: fmt.Errorf("expected prefix %s", f.Prefix)}
+		}
+		parts = parts[1:]
+	}
+
+	var payload string
+	if f.DateLayout != "" {
+		if _, err := time.Parse(f.DateLayout, parts[0]); err != nil {
+			return "", &NumberError{Input: s, Reason: fmt.Errorf("invalid date %s", parts[0])}
+		}
+		payload = parts[0]
+		parts = parts[1:]
+	}
+
+	sequence, ok := normalizeSequence(parts[0])
+	if !ok || len(sequence) != f.SequenceLength {
+		return "", &NumberError{Input: s, Reason: fmt.Errorf("expected %d base32 symbols sequence", f.SequenceLength)}
+	}
+	payload += sequence
+
+	if check, ok := normalizeSequence(parts[1]); !ok || len(check) != 1 || check[0] != checkSymbol(payload) {
+		return "", &NumberError{Input: s, Reason: ErrNumberChecksumMismatch}
+	}
+
+	return Number(f.join(payload, checkSymbol(payload))), nil
+}
+
+func (f NumberFormat) validate() error {
+	if !numberPrefix.MatchString(f.Prefix) {
+		return fmt.Errorf("prefix %q is not made of up to 8 uppercase letters", f.Prefix)
+	}
+
+	if f.SequenceLength < 1 || f.SequenceLength > 12 {
+		return fmt.Errorf("sequence length %d is not between 1 and 12", f.SequenceLength)
+	}
+
+	if date := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC).Format(f.DateLayout); strings.Trim(date, "0123456789") != "" {
+		return fmt.Errorf("date layout %q does not only produce digits", f.DateLayout)
+	}
+
+	if n := len(f.join(strings.Repeat("0", len(f.DateLayout)+f.SequenceLength), '0')); n > maxNumberLength {
+		return fmt.Errorf("numbers are %d characters long, more than %d", n, maxNumberLength)
+	}
+
+	return nil
+}
+
+// capacity returns the number of sequences that fit in the format
+func (f NumberFormat) capacity() uint64 {
+	return 1 << (5 * f.SequenceLength)
+}
+
+// segments returns the number of dash separated segments of the numbers of the format
+func (f NumberFormat) segments() int {
+	n := 2
+	if f.Prefix != "" {
+		n++
+	}
+	if f.DateLayout != "" {
+		n++
+	}
+
+	return n
+}
+
+// join builds the number from the payload (date and sequence) and its check symbol
+func (f NumberFormat) join(payload string, check byte) string {
+	var parts []string
+	if f.Prefix != "" {
+		parts = append(parts, f.Prefix)
+	}
+
+	sequence := payload
+	if f.DateLayout != "" {
+		date := len(payload) - f.SequenceLength
+		parts = append(parts, payload[:date])
+		sequence = payload[date:]
+	}
+
+	return strings.Join(append(parts, sequence, string(check)), numberSeparator)
+}
+
 // IsZero reports whether n represents the zero Number
 func (n Number) IsZero() bool {
-	return n == [32]byte{}
+	return n == ""
 }
 
 // String returns the Number as string
 func (n Number) String() string {
-	return string(n[:])
+	return string(n)
+}
+
+// normalizeSequence maps the Crockford aliases to their symbols
+// It reports false when s contains a symbol outside the alphabet
+func normalizeSequence(s string) (string, bool) {
+	b := []byte(s)
+	for i, c := range b {
+		switch c {
+		case 'O':
+			b[i] = '0'
+		case 'I', 'L':
+			b[i] = '1'
+		}
+		if strings.IndexByte(crockford, b[i]) < 0 {
+			return "", false
+		}
+	}
+
+	return string(b), true
+}
+
+// checkSymbol returns the check symbol of the payload using the Luhn mod N algorithm over the Crockford alphabet
+// It detects every single symbol error and most transpositions of adjacent symbols
+func checkSymbol(payload string) byte {
+	const n = len(crockford)
+
+	factor, sum := 2, 0
+	for i := len(payload) - 1; i >= 0; i-- {
+		addend := factor * strings.IndexByte(crockford, payload[i])
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+
+	return crockford[(n-sum%n)%n]
 }
@@ -0,0 +1,74 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNumberNotAllocated is returned when a NumberAllocator could not allocate a Number
+	ErrNumberNotAllocated = errors.New("could not allocate order number")
+)
+
+var (
+	_ NumberAllocator = &MemoryNumberAllocator{}
+)
+
+// NumberAllocator represents the layer handing out the numbers of the orders
+// Numbers are allocated from a sequence per Tenant, formatted with the NumberFormat of the tenant
+type NumberAllocator interface {
+	Allocate(ctx context.Context, tenant Tenant, at time.Time) (Number, error)
+}
+
+// NumberFormats maps the tenants to the format of their numbers
+// Formats must differ from each other (e.g. by prefix), so that numbers are unique across tenants
+type NumberFormats map[Tenant]NumberFormat
+
+// Format returns the Number of the tenant with the given date and sequence
+// It returns ErrNumberNotFormatted when the tenant has no format
+func (fs NumberFormats) Format(tenant Tenant, at time.Time, seq uint64) (Number, error) {
+	f, ok := fs[tenant]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown tenant %q", ErrNumberNotFormatted, tenant)
+	}
+
+	return f.Format(at, seq)
+}
+
+// MemoryNumberAllocator represents a NumberAllocator keeping its sequences in memory
+// It is meant to be used in tests, sequences restart from 1 on every new allocator
+type MemoryNumberAllocator struct {
+	formats NumberFormats
+
+	mu   sync.Mutex
+	seqs map[Tenant]uint64
+}
+
+// NewMemoryNumberAllocator returns a MemoryNumberAllocator for the given tenants
+func NewMemoryNumberAllocator(formats NumberFormats) *MemoryNumberAllocator {
+	return &MemoryNumberAllocator{
+		formats: formats,
+		seqs:    map[Tenant]uint64{},
+	}
+}
+
+// Allocate returns the next Number of the tenant
+func (a *MemoryNumberAllocator) Allocate(_ context.Context, tenant Tenant, at time.Time) (Number, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.formats[tenant]; !ok {
+		return "", fmt.Errorf("%w: unknown tenant %q", ErrNumberNotAllocated, tenant)
+	}
+
+	n, err := a.formats.Format(tenant, at, a.seqs[tenant]+1)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrNumberNotAllocated, err)
+	}
+
+	a.seqs[tenant]++
+	return n, nil
+}
@@ -0,0 +1,58 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/organization/order-service"
+)
+
+func TestMemoryNumberAllocator_Allocate(t *testing.T) {
+	ctx := context.Background()
+	clock := clockHelper(t)
+	allocator := NewMemoryNumberAllocator(NumberFormats{
+		"web":   {Prefix: "WEB", DateLayout: "060102", SequenceLength: 6},
+		"store": {Prefix: "STO", SequenceLength: 6},
+	})
+
+	t.Run("sequential per tenant", func(t *testing.T) {
+		for _, want := range []struct {
+			tenant Tenant
+			number Number
+		}{
+			{tenant: "web", number: mustFormat(t, "WEB", "060102", 1)},
+			{tenant: "web", number: mustFormat(t, "WEB", "060102", 2)},
+			{tenant: "store", number: mustFormat(t, "STO", "", 1)},
+			{tenant: "web", number: mustFormat(t, "WEB", "060102", 3)},
+		} {
+			n, err := allocator.Allocate(ctx, want.tenant, clock.Now())
+			if err != nil {
+				t.Fatalf("could not allocate number: %s", err)
+			}
+
+			if n != want.number {
+				t.Error("could not match number")
+				t.Errorf("got: %s", n)
+				t.Errorf("want: %s", want.number)
+			}
+		}
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		if _, err := allocator.Allocate(ctx, "marketplace", clock.Now()); !errors.Is(err, ErrNumberNotAllocated) {
+			t.Fatalf("could not match error: %s", err)
+		}
+	})
+}
+
+func mustFormat(t *testing.T, prefix, layout string, seq uint64) Number {
+	t.Helper()
+
+	n, err := NumberFormat{Prefix: prefix, DateLayout: layout, SequenceLength: 6}.Format(clockHelper(t).Now(), seq)
+	if err != nil {
+		t.Fatalf("could not format number: %s", err)
+	}
+
+	return n
+}
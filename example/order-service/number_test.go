@@ -1,30 +1,36 @@
 package order_test
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	. "github.com/organization/order-service"
 )
 
 func TestGenerateNumber(t *testing.T) {
 	n1 := GenerateNumber()
-	if string(n1[:]) == "" {
+	if n1.IsZero() {
 		t.Fatalf("could match order number as an empty string")
 	}
 
 	n2 := GenerateNumber()
-	if string(n2[:]) == "" {
+	if n2.IsZero() {
 		t.Fatalf("could match order number as an empty string")
 	}
 
 	if n1 == n2 {
 		t.Fatalf("could match random order numbers")
 	}
+
+	if _, err := ParseNumber(n1.String()); err != nil {
+		t.Fatalf("could not parse generated number: %s", err)
+	}
 }
 
 func TestNumber_IsZero(t *testing.T) {
 	t.Run("zero value", func(t *testing.T) {
-		if !(Number{}).IsZero() {
+		if !Number("").IsZero() {
 			t.Fatalf("could not match zero value number")
 		}
 	})
@@ -35,3 +41,114 @@ func TestNumber_IsZero(t *testing.T) {
 		}
 	})
 }
+
+func TestNumberFormat_Format(t *testing.T) {
+	date := time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		format NumberFormat
+		seq    uint64
+		want   Number
+	}{
+		"default":   {format: DefaultNumberFormat, seq: 4583, want: "ORD-230321-0004F7-9"},
+		"no prefix": {format: NumberFormat{DateLayout: "20060102", SequenceLength: 4}, seq: 31, want: "20230321-000Z-D"},
+		"no date":   {format: NumberFormat{Prefix: "SO", SequenceLength: 8}, seq: 1, want: "SO-00000001-Y"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.format.Format(date, tt.seq)
+			if err != nil {
+				t.Fatalf("could not format number: %s", err)
+			}
+
+			if got != tt.want {
+				t.Error("could not match number")
+				t.Errorf("got: %s", got)
+				t.Errorf("want: %s", tt.want)
+			}
+
+			parsed, err := tt.format.Parse(got.String())
+			if err != nil {
+				t.Fatalf("could not parse formatted number: %s", err)
+			}
+
+			if parsed != got {
+				t.Error("could not match number once parsed")
+				t.Errorf("got: %s", parsed)
+				t.Errorf("want: %s", got)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := map[string]struct {
+			format NumberFormat
+			seq    uint64
+		}{
+			"sequence overflow": {format: NumberFormat{SequenceLength: 2}, seq: 1024},
+			"lowercase prefix":  {format: NumberFormat{Prefix: "ord", SequenceLength: 6}},
+			"textual date":      {format: NumberFormat{DateLayout: "Jan06", SequenceLength: 6}},
+			"no sequence":       {format: NumberFormat{Prefix: "ORD"}},
+			"too long":          {format: NumberFormat{Prefix: "ORDERNUM", DateLayout: "20060102150405", SequenceLength: 12}},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				if _, err := tt.format.Format(date, tt.seq); !errors.Is(err, ErrNumberNotFormatted) {
+					t.Fatalf("could not match error: %s", err)
+				}
+			})
+		}
+	})
+}
+
+func TestParseNumber(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, raw := range []string{
+			"ORD-230321-0004F7-9",
+			" ord-230321-0004f7-9 ",
+			"ORD-230321-O004F7-9",
+		} {
+			n, err := ParseNumber(raw)
+			if err != nil {
+				t.Fatalf("could not parse number %q: %s", raw, err)
+			}
+
+			if n != "ORD-230321-0004F7-9" {
+				t.Errorf("could not match number parsed from %q: %s", raw, n)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		tests := map[string]error{
+			"":                         nil,
+			"ORD-230321-0004F7":        nil,
+			"INV-230321-0004F7-9":      nil,
+			"ORD-231321-0004F7-9":      nil,
+			"ORD-230321-0004U7-9":      nil,
+			"ORD-230321-004F7-9":       nil,
+			"ORD-230321-0004F8-9":      ErrNumberChecksumMismatch,
+			"ORD-230321-000F47-9":      ErrNumberChecksumMismatch,
+			"ORD-230321-0004F7-8":      ErrNumberChecksumMismatch,
+			"3f2b8c9d0e1f4a5b6c7d8e9f": nil,
+		}
+
+		for raw, reason := range tests {
+			_, err := ParseNumber(raw)
+			if !errors.Is(err, ErrNumberNotParsed) {
+				t.Fatalf("could not match error for %q: %s", raw, err)
+			}
+
+			var nErr *NumberError
+			if !errors.As(err, &nErr) || nErr.Input != raw {
+				t.Fatalf("could not match number error for %q: %s", raw, err)
+			}
+
+			if reason != nil && !errors.Is(err, reason) {
+				t.Fatalf("could not match reason for %q: %s", raw, err)
+			}
+		}
+	})
+}
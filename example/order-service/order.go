@@ -8,61 +8,142 @@ import (
 
 // Domain errors raised by the order aggregate
 var (
+	ErrNotPlaced    = errors.New("could not place the order")
 	ErrNotShipped   = errors.New("could not mark the order as shipped")
 	ErrNotDelivered = errors.New("could not mark the order as delivered")
+	ErrNotCancelled = errors.New("could not cancel the order")
+	// ErrShippingAddressNotChanged is returned when the shipping address can no longer be changed
+	ErrShippingAddressNotChanged = errors.New("could not change the shipping address of the order")
 )
 
 // Order represents an order aggregate
 type Order struct {
-	ID          ID
-	Number      Number
-	Status      Status
-	PlacedBy    UserID
-	PlacedAt    time.Time
-	ShippedAt   time.Time
-	DeliveredAt time.Time
+	ID                 ID
+	Number             Number
+	Status             Status
+	PlacedBy           UserID
+	Items              []LineItem
+	ShippingAddress    Address
+	PlacedAt           time.Time
+	ShippedAt          time.Time
+	DeliveredAt        time.Time
+	CancelledAt        time.Time
+	CancellationReason CancellationReason
+	// Version is the version of the order as stored in the repository, zero when it was never stored
+	// Repositories use it for optimistic concurrency control and increment it on every successful write
+	Version int
+
+	events []Event
+	// stored is the number of events already stored by a repository
+	stored int
 }
 
-// Place places a new order
+// Place places a new order at the time read from the clock
 // It is a factory function that uses the ubiquitous language of the domain
-func Place(number Number, placedBy UserID) *Order {
-	return &Order{
-		ID:       NewID(),
+// It returns ErrNotPlaced when the id or the number are missing or the items violate the domain invariants
+func Place(clock Clock, id ID, number Number, placedBy UserID, items []LineItem) (*Order, error) {
+	if id.IsZero() {
+		return nil, fmt.Errorf("%w: missing id", ErrNotPlaced)
+	}
+
+	if number.IsZero() {
+		return nil, fmt.Errorf("%w: missing number", ErrNotPlaced)
+	}
+
+	if err := validateItems(items); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotPlaced, err)
+	}
+
+	o := &Order{
+		ID:       id,
 		Number:   number,
 		Status:   Placed,
 		PlacedBy: placedBy,
-		PlacedAt: time.Now(),
+		Items:    append([]LineItem(nil), items...),
+		PlacedAt: clock.Now(),
 	}
+
+	o.record(OrderPlaced{OrderID: o.ID, Number: o.Number, PlacedBy: o.PlacedBy, Items: append([]LineItem(nil), o.Items...), At: o.PlacedAt})
+	return o, nil
 }
 
-// MarkAsShipped marks an order as shipped
+// Total returns the price of the order, summing up all its items
+// It fails only if the items violate the invariants checked when the order was placed
+func (o *Order) Total() (Money, error) {
+	return totalOf(o.Items)
+}
+
+// Clone returns a copy of the order sharing no mutable state with it, without its pending events
+// It allows the layers holding on to orders, such as caches, to hand them out without being changed by the callers
+func (o *Order) Clone() *Order {
+	c := *o
+	c.Items = append([]LineItem(nil), o.Items...)
+	c.events, c.stored = nil, 0
+	return &c
+}
+
+// MarkAsShipped marks an order as shipped at the time read from the clock
 // It returns ErrNotShipped when the operation violated the domain invariants
-func (o *Order) MarkAsShipped() error {
-	switch {
-	case o.PlacedAt.IsZero():
-		return fmt.Errorf("%w: not placed", ErrNotShipped)
-	case o.Status == Shipped:
-		return fmt.Errorf("%w: already shipped", ErrNotShipped)
-	case o.Status == Delivered:
-		return fmt.Errorf("%w: already delivered", ErrNotShipped)
+func (o *Order) MarkAsShipped(clock Clock) error {
+	if err := o.transitionTo(Shipped); err != nil {
+		return err
 	}
 
-	o.Status = Shipped
-	o.ShippedAt = time.Now()
+	o.ShippedAt = clock.Now()
+	o.record(OrderShipped{OrderID: o.ID, At: o.ShippedAt})
 	return nil
 }
 
-// MarkAsDelivered marks an order as delivered
+// MarkAsDelivered marks an order as delivered at the time read from the clock
 // It returns ErrNotDelivered when the operation violated the domain invariants
-func (o *Order) MarkAsDelivered() error {
-	switch {
-	case o.ShippedAt.IsZero():
-		return fmt.Errorf("%w: not shipped", ErrNotDelivered)
-	case o.Status == Delivered:
-		return fmt.Errorf("%w: already delivered", ErrNotDelivered)
+func (o *Order) MarkAsDelivered(clock Clock) error {
+	if err := o.transitionTo(Delivered); err != nil {
+		return err
+	}
+
+	o.DeliveredAt = clock.Now()
+	o.record(OrderDelivered{OrderID: o.ID, At: o.DeliveredAt})
+	return nil
+}
+
+// Cancel cancels an order for the given reason at the time read from the clock
+// It returns ErrNotCancelled when the operation violated the domain invariants
+func (o *Order) Cancel(clock Clock, reason CancellationReason) error {
+	if reason.IsZero() {
+		return fmt.Errorf("%w: missing reason", ErrNotCancelled)
+	}
+
+	if err := o.transitionTo(Cancelled); err != nil {
+		return err
 	}
 
-	o.Status = Delivered
-	o.DeliveredAt = time.Now()
+	o.CancelledAt = clock.Now()
+	o.CancellationReason = reason
+	o.record(OrderCancelled{OrderID: o.ID, Reason: o.CancellationReason, At: o.CancelledAt})
 	return nil
 }
+
+// ChangeShippingAddress changes the address the order ships to, recording the change at the time read from the clock
+// It returns ErrShippingAddressNotChanged when the order is no longer placed
+func (o *Order) ChangeShippingAddress(clock Clock, address Address) error {
+	if address.IsZero() {
+		return fmt.Errorf("%w: missing address", ErrShippingAddressNotChanged)
+	}
+
+	if o.Status != Placed {
+		return fmt.Errorf("%w: %w", ErrShippingAddressNotChanged, addressRejectionReason(o.Status))
+	}
+
+	o.ShippingAddress = address
+	o.record(ShippingAddressChanged{OrderID: o.ID, Address: o.ShippingAddress, At: clock.Now()})
+	return nil
+}
+
+// addressRejectionReason returns why the shipping address of an order in the given status cannot be changed
+func addressRejectionReason(s Status) error {
+	if s.IsZero() {
+		return errors.New("not placed")
+	}
+
+	return fmt.Errorf("already %s, the address can only be changed while placed", s)
+}
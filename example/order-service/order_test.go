@@ -2,6 +2,8 @@ package order_test
 
 import (
 	"errors"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,13 +13,21 @@ import (
 )
 
 func TestPlace(t *testing.T) {
+	clock := clockHelper(t)
+	id := NewID()
 	n := GenerateNumber()
 	uID := userIDHelper(t)
+	items := itemsHelper(t)
 
-	o := Place(n, uID)
+	o, err := Place(clock, id, n, uID, items)
+	if err != nil {
+		t.Fatalf("could not place the order: %s", err)
+	}
 
-	if o.ID.IsZero() {
-		t.Errorf("could not match id: is zero: %s", o.ID)
+	if o.ID != id {
+		t.Error("could not match id")
+		t.Errorf("got: %s", o.ID)
+		t.Errorf("want: %s", id)
 	}
 
 	if o.Number != n {
@@ -36,7 +46,13 @@ func TestPlace(t *testing.T) {
 		t.Errorf("want: %s", o.PlacedBy)
 	}
 
-	if time.Until(o.PlacedAt) > time.Second {
+	if !reflect.DeepEqual(o.Items, items) {
+		t.Error("could not match items")
+		t.Errorf("got: %v", o.Items)
+		t.Errorf("want: %v", items)
+	}
+
+	if !o.PlacedAt.Equal(clock.Now()) {
 		t.Errorf("could not match placed at time: %s", o.PlacedAt)
 	}
 
@@ -49,10 +65,112 @@ func TestPlace(t *testing.T) {
 	}
 }
 
+func TestPlace_InvalidItems(t *testing.T) {
+	clock := clockHelper(t)
+	tests := map[string][]LineItem{
+		"no items":          nil,
+		"zero quantity":     {{SKU: "SKU-1", Quantity: 0, UnitPrice: NewMoney(100, EUR)}},
+		"negative quantity": {{SKU: "SKU-1", Quantity: -1, UnitPrice: NewMoney(100, EUR)}},
+		"negative price":    {{SKU: "SKU-1", Quantity: 1, UnitPrice: NewMoney(-100, EUR)}},
+		"missing sku":       {{Quantity: 1, UnitPrice: NewMoney(100, EUR)}},
+		"mixed currencies": {
+			{SKU: "SKU-1", Quantity: 1, UnitPrice: NewMoney(100, EUR)},
+			{SKU: "SKU-2", Quantity: 1, UnitPrice: NewMoney(100, USD)},
+		},
+		"missing currency": {{SKU: "SKU-1", Quantity: 1, UnitPrice: Money{}}},
+		"duplicated sku": {
+			{SKU: "SKU-1", Quantity: 1, UnitPrice: NewMoney(100, EUR)},
+			{SKU: "SKU-1", Quantity: 2, UnitPrice: NewMoney(100, EUR)},
+		},
+	}
+
+	for name, items := range tests {
+		t.Run(name, func(t *testing.T) {
+			o, err := Place(clock, NewID(), GenerateNumber(), userIDHelper(t), items)
+			if !errors.Is(err, ErrNotPlaced) {
+				t.Fatalf("could not match error: %s", err)
+			}
+
+			if o != nil {
+				t.Fatalf("could not match a nil order: %v", o)
+			}
+		})
+	}
+}
+
+func TestPlace_MissingIdentity(t *testing.T) {
+	clock := clockHelper(t)
+
+	t.Run("missing id", func(t *testing.T) {
+		o, err := Place(clock, ID{}, GenerateNumber(), userIDHelper(t), itemsHelper(t))
+		if !errors.Is(err, ErrNotPlaced) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+
+	t.Run("missing number", func(t *testing.T) {
+		o, err := Place(clock, NewID(), "", userIDHelper(t), itemsHelper(t))
+		if !errors.Is(err, ErrNotPlaced) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		if o != nil {
+			t.Fatalf("could not match a nil order: %v", o)
+		}
+	})
+}
+
+func TestOrder_Total(t *testing.T) {
+	o := &Order{Items: []LineItem{
+		{SKU: "SKU-1", Quantity: 2, UnitPrice: NewMoney(1050, EUR)},
+		{SKU: "SKU-2", Quantity: 1, UnitPrice: NewMoney(399, EUR)},
+	}}
+
+	got, err := o.Total()
+	if err != nil {
+		t.Fatalf("could not compute total: %s", err)
+	}
+
+	if want := NewMoney(2499, EUR); got != want {
+		t.Error("could not match total")
+		t.Errorf("got: %s", got)
+		t.Errorf("want: %s", want)
+	}
+}
+
+func TestOrder_Clone(t *testing.T) {
+	o := placeHelper(t)
+	c := o.Clone()
+
+	if !reflect.DeepEqual(c.Items, o.Items) || c.ID != o.ID || c.Status != o.Status || !c.PlacedAt.Equal(o.PlacedAt) {
+		t.Error("could not match clone")
+		t.Errorf("got: %v", c)
+		t.Errorf("want: %v", o)
+	}
+
+	if events := c.Events(); len(events) != 0 {
+		t.Fatalf("could not match clone without pending events: %v", events)
+	}
+
+	if err := c.MarkAsShipped(clockHelper(t)); err != nil {
+		t.Fatalf("could not mark clone as shipped: %s", err)
+	}
+	c.Items[0].Quantity++
+
+	if o.Status != Placed || o.Items[0].Quantity == c.Items[0].Quantity || len(o.Events()) != 1 {
+		t.Fatalf("could not match order left untouched by its clone: %v", o)
+	}
+}
+
 func TestOrder_MarkAsShipped(t *testing.T) {
+	clock := clockHelper(t)
 	id := NewID()
 	n := GenerateNumber()
-	placedAt := time.Now()
+	placedAt := clock.Now()
 	uID := userIDHelper(t)
 
 	t.Run("placed", func(t *testing.T) {
@@ -66,7 +184,8 @@ func TestOrder_MarkAsShipped(t *testing.T) {
 			DeliveredAt: time.Time{},
 		}
 
-		if err := o.MarkAsShipped(); err != nil {
+		clock.Advance(time.Hour)
+		if err := o.MarkAsShipped(clock); err != nil {
 			t.Fatalf("could not mark the order as shipped: %s", err)
 		}
 
@@ -98,7 +217,7 @@ func TestOrder_MarkAsShipped(t *testing.T) {
 			t.Errorf("want: %s", o.PlacedBy)
 		}
 
-		if time.Until(o.ShippedAt) > time.Second {
+		if !o.ShippedAt.Equal(clock.Now()) {
 			t.Errorf("could not match shipped at time: %s", o.ShippedAt)
 		}
 
@@ -113,12 +232,12 @@ func TestOrder_MarkAsShipped(t *testing.T) {
 			Number:      n,
 			Status:      Shipped,
 			PlacedBy:    uID,
-			PlacedAt:    time.Now(),
-			ShippedAt:   time.Now(),
+			PlacedAt:    clock.Now(),
+			ShippedAt:   clock.Now(),
 			DeliveredAt: time.Time{},
 		}
 
-		if err := o.MarkAsShipped(); !errors.Is(err, ErrNotShipped) {
+		if err := o.MarkAsShipped(clock); !errors.Is(err, ErrNotShipped) {
 			t.Fatalf("could mark the order as shipped: %s", err)
 		}
 	})
@@ -130,21 +249,22 @@ func TestOrder_MarkAsShipped(t *testing.T) {
 			Status:      Delivered,
 			PlacedBy:    uID,
 			PlacedAt:    placedAt,
-			ShippedAt:   time.Now(),
-			DeliveredAt: time.Now(),
+			ShippedAt:   clock.Now(),
+			DeliveredAt: clock.Now(),
 		}
 
-		if err := o.MarkAsShipped(); !errors.Is(err, ErrNotShipped) {
+		if err := o.MarkAsShipped(clock); !errors.Is(err, ErrNotShipped) {
 			t.Fatalf("could mark the order as shipped: %s", err)
 		}
 	})
 }
 
 func TestOrder_MarkAsDelivered(t *testing.T) {
+	clock := clockHelper(t)
 	id := NewID()
 	n := GenerateNumber()
-	placedAt := time.Now()
-	shippedAt := time.Now()
+	placedAt := clock.Now()
+	shippedAt := clock.Now()
 	uID := userIDHelper(t)
 
 	t.Run("placed", func(t *testing.T) {
@@ -158,7 +278,7 @@ func TestOrder_MarkAsDelivered(t *testing.T) {
 			DeliveredAt: time.Time{},
 		}
 
-		if err := o.MarkAsDelivered(); !errors.Is(err, ErrNotDelivered) {
+		if err := o.MarkAsDelivered(clock); !errors.Is(err, ErrNotDelivered) {
 			t.Fatalf("could mark the order as delivered: %s", err)
 		}
 	})
@@ -174,7 +294,8 @@ func TestOrder_MarkAsDelivered(t *testing.T) {
 			DeliveredAt: time.Time{},
 		}
 
-		if err := o.MarkAsDelivered(); err != nil {
+		clock.Advance(time.Hour)
+		if err := o.MarkAsDelivered(clock); err != nil {
 			t.Fatalf("could not mark the order as delivered: %s", err)
 		}
 
@@ -212,7 +333,7 @@ func TestOrder_MarkAsDelivered(t *testing.T) {
 			t.Errorf("want: %s", o.ShippedAt)
 		}
 
-		if time.Until(o.DeliveredAt) > time.Second {
+		if !o.DeliveredAt.Equal(clock.Now()) {
 			t.Errorf("could not match delivered at time: %s", o.DeliveredAt)
 		}
 	})
@@ -225,15 +346,157 @@ func TestOrder_MarkAsDelivered(t *testing.T) {
 			PlacedBy:    uID,
 			PlacedAt:    placedAt,
 			ShippedAt:   shippedAt,
-			DeliveredAt: time.Now(),
+			DeliveredAt: clock.Now(),
 		}
 
-		if err := o.MarkAsShipped(); !errors.Is(err, ErrNotShipped) {
+		if err := o.MarkAsShipped(clock); !errors.Is(err, ErrNotShipped) {
 			t.Fatalf("could not mark the order as already delivered: %s", err)
 		}
 	})
 }
 
+func TestOrder_Cancel(t *testing.T) {
+	clock := clockHelper(t)
+	id := NewID()
+	n := GenerateNumber()
+	placedAt := clock.Now()
+	shippedAt := clock.Now()
+	uID := userIDHelper(t)
+
+	t.Run("placed", func(t *testing.T) {
+		o := &Order{
+			ID:       id,
+			Number:   n,
+			Status:   Placed,
+			PlacedBy: uID,
+			PlacedAt: placedAt,
+		}
+
+		clock.Advance(time.Hour)
+		if err := o.Cancel(clock, FraudSuspected); err != nil {
+			t.Fatalf("could not cancel the order: %s", err)
+		}
+
+		if o.Status != Cancelled {
+			t.Errorf("could not match cancelled status: %s", o.Status)
+		}
+
+		if o.CancellationReason != FraudSuspected {
+			t.Error("could not match cancellation reason")
+			t.Errorf("got: %s", o.CancellationReason)
+			t.Errorf("want: %s", FraudSuspected)
+		}
+
+		if o.PlacedAt != placedAt {
+			t.Error("could not match placed at")
+			t.Errorf("got: %s", o.PlacedAt)
+			t.Errorf("want: %s", placedAt)
+		}
+
+		if !o.CancelledAt.Equal(clock.Now()) {
+			t.Errorf("could not match cancelled at time: %s", o.CancelledAt)
+		}
+	})
+
+	t.Run("shipped", func(t *testing.T) {
+		o := &Order{
+			ID:        id,
+			Number:    n,
+			Status:    Shipped,
+			PlacedBy:  uID,
+			PlacedAt:  placedAt,
+			ShippedAt: shippedAt,
+		}
+
+		if err := o.Cancel(clock, CustomerRequest); err != nil {
+			t.Fatalf("could not cancel the order: %s", err)
+		}
+
+		if o.Status != Cancelled {
+			t.Errorf("could not match cancelled status: %s", o.Status)
+		}
+
+		if err := o.MarkAsDelivered(clock); !errors.Is(err, ErrNotDelivered) {
+			t.Fatalf("could mark a cancelled order as delivered: %s", err)
+		}
+	})
+
+	t.Run("delivered", func(t *testing.T) {
+		o := &Order{
+			ID:          id,
+			Number:      n,
+			Status:      Delivered,
+			PlacedBy:    uID,
+			PlacedAt:    placedAt,
+			ShippedAt:   shippedAt,
+			DeliveredAt: clock.Now(),
+		}
+
+		if err := o.Cancel(clock, CustomerRequest); !errors.Is(err, ErrNotCancelled) {
+			t.Fatalf("could cancel a delivered order: %s", err)
+		}
+
+		if !o.CancelledAt.IsZero() {
+			t.Errorf("could not match cancelled at time as zero: %s", o.CancelledAt)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		o := &Order{
+			ID:                 id,
+			Number:             n,
+			Status:             Cancelled,
+			PlacedBy:           uID,
+			PlacedAt:           placedAt,
+			CancelledAt:        clock.Now(),
+			CancellationReason: OutOfStock,
+		}
+
+		if err := o.Cancel(clock, CustomerRequest); !errors.Is(err, ErrNotCancelled) {
+			t.Fatalf("could cancel an already cancelled order: %s", err)
+		}
+
+		if err := o.MarkAsShipped(clock); !errors.Is(err, ErrNotShipped) {
+			t.Fatalf("could mark a cancelled order as shipped: %s", err)
+		}
+	})
+
+	t.Run("missing reason", func(t *testing.T) {
+		o := &Order{
+			ID:       id,
+			Number:   n,
+			Status:   Placed,
+			PlacedBy: uID,
+			PlacedAt: placedAt,
+		}
+
+		if err := o.Cancel(clock, ""); !errors.Is(err, ErrNotCancelled) {
+			t.Fatalf("could cancel the order without a reason: %s", err)
+		}
+	})
+}
+
+func placeHelper(t *testing.T) *Order {
+	t.Helper()
+
+	clock := clockHelper(t)
+	o, err := Place(clock, NewID(), GenerateNumber(), userIDHelper(t), itemsHelper(t))
+	if err != nil {
+		t.Fatalf("could not place the order: %s", err)
+	}
+
+	return o
+}
+
+func itemsHelper(t *testing.T) []LineItem {
+	t.Helper()
+
+	return []LineItem{
+		{SKU: "SKU-1", Quantity: 2, UnitPrice: NewMoney(1050, EUR)},
+		{SKU: "SKU-2", Quantity: 1, UnitPrice: NewMoney(399, EUR)},
+	}
+}
+
 func userIDHelper(t *testing.T) UserID {
 	uID, err := ParseUserID(uuid.NewString())
 	if err != nil {
@@ -242,3 +505,66 @@ func userIDHelper(t *testing.T) UserID {
 
 	return uID
 }
+
+func TestOrder_ChangeShippingAddress(t *testing.T) {
+	clock := clockHelper(t)
+	address := addressHelper(t)
+
+	t.Run("placed", func(t *testing.T) {
+		o := placeHelper(t)
+		o.PullEvents()
+
+		if err := o.ChangeShippingAddress(clock, address); err != nil {
+			t.Fatalf("could not change the shipping address: %s", err)
+		}
+
+		if !o.ShippingAddress.Equal(address) {
+			t.Error("could not match shipping address")
+			t.Errorf("got: %s", o.ShippingAddress)
+			t.Errorf("want: %s", address)
+		}
+
+		events := o.PullEvents()
+		if len(events) != 1 {
+			t.Fatalf("could not match number of events: %d", len(events))
+		}
+
+		if e, ok := events[0].(ShippingAddressChanged); !ok || !e.Address.Equal(address) || e.AggregateID() != o.ID {
+			t.Errorf("could not match shipping address changed event: %#v", events[0])
+		}
+	})
+
+	t.Run("missing address", func(t *testing.T) {
+		o := placeHelper(t)
+
+		if err := o.ChangeShippingAddress(clock, Address{}); !errors.Is(err, ErrShippingAddressNotChanged) {
+			t.Fatalf("could change the shipping address to a zero address: %s", err)
+		}
+	})
+
+	for _, status := range []Status{Shipped, Delivered, Cancelled} {
+		t.Run(status.String(), func(t *testing.T) {
+			o := placeHelper(t)
+			o.Status = status
+
+			err := o.ChangeShippingAddress(clock, address)
+			if !errors.Is(err, ErrShippingAddressNotChanged) {
+				t.Fatalf("could change the shipping address: %s", err)
+			}
+
+			if !strings.Contains(err.Error(), "already "+status.String()) {
+				t.Errorf("could not match rejection reason: %s", err)
+			}
+
+			if !o.ShippingAddress.IsZero() {
+				t.Errorf("could not match shipping address as zero: %s", o.ShippingAddress)
+			}
+		})
+	}
+}
+
+func clockHelper(t *testing.T) *FakeClock {
+	t.Helper()
+
+	return NewFakeClock(time.Date(2023, time.March, 21, 9, 15, 0, 0, time.UTC))
+}
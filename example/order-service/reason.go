@@ -0,0 +1,38 @@
+package order
+
+import "errors"
+
+var (
+	// ErrCancellationReasonNotParsed represents an error returned by a cancellation reason value type (aka value objects)
+	ErrCancellationReasonNotParsed = errors.New("could not parse cancellation reason")
+)
+
+const (
+	CustomerRequest CancellationReason = "customer_request"
+	PaymentFailed   CancellationReason = "payment_failed"
+	FraudSuspected  CancellationReason = "fraud_suspected"
+	OutOfStock      CancellationReason = "out_of_stock"
+)
+
+// CancellationReason represents the reason code explaining why an order was cancelled
+type CancellationReason string
+
+// ParseCancellationReason returns a CancellationReason or an error if the given string is not a known reason code
+func ParseCancellationReason(s string) (CancellationReason, error) {
+	switch r := CancellationReason(s); r {
+	case CustomerRequest, PaymentFailed, FraudSuspected, OutOfStock:
+		return r, nil
+	default:
+		return "", ErrCancellationReasonNotParsed
+	}
+}
+
+// IsZero reports whether r represents the zero CancellationReason
+func (r CancellationReason) IsZero() bool {
+	return r == ""
+}
+
+// String returns the CancellationReason as string
+func (r CancellationReason) String() string {
+	return string(r)
+}
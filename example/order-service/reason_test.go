@@ -0,0 +1,50 @@
+package order_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/organization/order-service"
+)
+
+func TestParseCancellationReason(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, raw := range []string{"customer_request", "payment_failed", "fraud_suspected", "out_of_stock"} {
+			r, err := ParseCancellationReason(raw)
+			if err != nil {
+				t.Fatalf("could not parse cancellation reason: %s", err)
+			}
+
+			if r.String() != raw {
+				t.Error("could not match cancellation reason as its raw format")
+				t.Errorf("got: %s", r)
+				t.Fatalf("want: %s", raw)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		r, err := ParseCancellationReason("an invalid reason")
+		if !errors.Is(err, ErrCancellationReasonNotParsed) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		if !r.IsZero() {
+			t.Fatalf("could not match an empty cancellation reason: %s", r)
+		}
+	})
+}
+
+func TestCancellationReason_IsZero(t *testing.T) {
+	t.Run("zero value", func(t *testing.T) {
+		if !CancellationReason("").IsZero() {
+			t.Fatalf("could not match zero value cancellation reason")
+		}
+	})
+
+	t.Run("value", func(t *testing.T) {
+		if FraudSuspected.IsZero() {
+			t.Fatalf("could match zero value cancellation reason")
+		}
+	})
+}
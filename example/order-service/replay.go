@@ -0,0 +1,78 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotReplayed is returned when events cannot be replayed into an order
+var ErrNotReplayed = errors.New("could not replay the order events")
+
+// Replay rebuilds an order by applying the events it recorded, in the order they were recorded
+// It starts from the snapshot when not nil, the events being the ones recorded since the snapshot was taken,
+// otherwise the first event must be OrderPlaced
+// Events are facts, so they are applied without checking the domain invariants again
+// The rebuilt order has no pending events, its Version is left to the repository
+func Replay(snapshot *Order, events ...Event) (*Order, error) {
+	var o *Order
+	if snapshot != nil {
+		o = snapshot.Clone()
+	}
+
+	for _, e := range events {
+		if o == nil {
+			placed, ok := e.(OrderPlaced)
+			if !ok {
+				return nil, fmt.Errorf("%w: first event %s is not %s", ErrNotReplayed, e.EventName(), OrderPlaced{}.EventName())
+			}
+
+			o = &Order{
+				ID:       placed.OrderID,
+				Number:   placed.Number,
+				Status:   Placed,
+				PlacedBy: placed.PlacedBy,
+				Items:    append([]LineItem(nil), placed.Items...),
+				PlacedAt: placed.At,
+			}
+			continue
+		}
+
+		if e.AggregateID() != o.ID {
+			return nil, fmt.Errorf("%w: %s event of order %s", ErrNotReplayed, e.EventName(), e.AggregateID())
+		}
+
+		if err := o.apply(e); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNotReplayed, err)
+		}
+	}
+
+	if o == nil {
+		return nil, fmt.Errorf("%w: no events", ErrNotReplayed)
+	}
+
+	return o, nil
+}
+
+// apply changes the state of the order as told by an event it recorded
+func (o *Order) apply(e Event) error {
+	switch e := e.(type) {
+	case OrderShipped:
+		o.Status = Shipped
+		o.ShippedAt = e.At
+	case OrderDelivered:
+		o.Status = Delivered
+		o.DeliveredAt = e.At
+	case OrderCancelled:
+		o.Status = Cancelled
+		o.CancelledAt = e.At
+		o.CancellationReason = e.Reason
+	case ShippingAddressChanged:
+		o.ShippingAddress = e.Address
+	case OrderPlaced:
+		return fmt.Errorf("order %s placed twice", o.ID)
+	default:
+		return fmt.Errorf("unknown event %s", e.EventName())
+	}
+
+	return nil
+}
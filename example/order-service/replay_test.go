@@ -0,0 +1,98 @@
+package order_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/organization/order-service"
+)
+
+func TestReplay(t *testing.T) {
+	t.Run("replayed", func(t *testing.T) {
+		clock := clockHelper(t)
+		o, err := Place(clock, NewID(), GenerateNumber(), userIDHelper(t), itemsHelper(t))
+		if err != nil {
+			t.Fatalf("could not place the order: %s", err)
+		}
+
+		clock.Advance(time.Hour)
+		if err := o.ChangeShippingAddress(clock, addressHelper(t)); err != nil {
+			t.Fatalf("could not change the shipping address: %s", err)
+		}
+
+		clock.Advance(time.Hour)
+		if err := o.Cancel(clock, CustomerRequest); err != nil {
+			t.Fatalf("could not cancel the order: %s", err)
+		}
+
+		events := o.PullEvents()
+		got, err := Replay(nil, events...)
+		if err != nil {
+			t.Fatalf("could not replay the order: %s", err)
+		}
+
+		if !reflect.DeepEqual(got, o) {
+			t.Error("could not match replayed order")
+			t.Errorf("got: %#v", got)
+			t.Errorf("want: %#v", o)
+		}
+
+		if len(got.Events()) != 0 {
+			t.Errorf("could not match replayed order without pending events: %v", got.Events())
+		}
+	})
+
+	t.Run("from snapshot", func(t *testing.T) {
+		clock := clockHelper(t)
+		o := placeHelper(t)
+		o.PullEvents()
+		snapshot := *o
+
+		if err := o.MarkAsShipped(clock); err != nil {
+			t.Fatalf("could not mark the order as shipped: %s", err)
+		}
+
+		got, err := Replay(&snapshot, o.PullEvents()...)
+		if err != nil {
+			t.Fatalf("could not replay the order: %s", err)
+		}
+
+		if got.Status != Shipped || !got.ShippedAt.Equal(clock.Now()) {
+			t.Errorf("could not match order shipped since the snapshot: %#v", got)
+		}
+
+		if snapshot.Status != Placed {
+			t.Errorf("could not match snapshot left untouched: %s", snapshot.Status)
+		}
+	})
+
+	t.Run("not replayed", func(t *testing.T) {
+		o := placeHelper(t)
+		placed := o.PullEvents()[0]
+		shipped := OrderShipped{OrderID: o.ID, At: o.PlacedAt}
+
+		tests := map[string]struct {
+			snapshot *Order
+			events   []Event
+		}{
+			"no events":        {},
+			"not placed first": {events: []Event{shipped}},
+			"placed twice":     {events: []Event{placed, placed}},
+			"other order":      {events: []Event{placed, OrderShipped{OrderID: NewID()}}},
+			"placed on snapshot": {
+				snapshot: o,
+				events:   []Event{placed},
+			},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				if _, err := Replay(tt.snapshot, tt.events...); !errors.Is(err, ErrNotReplayed) {
+					t.Fatalf("could not match error: %s", err)
+				}
+			})
+		}
+	})
+}
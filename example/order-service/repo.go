@@ -8,10 +8,16 @@ import (
 var (
 	ErrNotFound = errors.New("could not find order")
 	ErrNotAdded = errors.New("could not add order")
+	// ErrConcurrentModification is returned when the order was modified by someone else since it was read
+	ErrConcurrentModification = errors.New("order was modified concurrently")
+	// ErrDuplicateNumber is returned when another order was already stored with the same Number
+	ErrDuplicateNumber = errors.New("order number already taken")
 )
 
 // Repo represents the layer to read/write data from/to the storage
 // You can also consider splitting this interface into multiple ones
+// Add must refuse with ErrConcurrentModification an order whose Version differs from the stored one
+// and with ErrDuplicateNumber an order whose Number is already taken by another order
 type Repo interface {
 	Get(ctx context.Context, id ID) (*Order, error)
 	Add(ctx context.Context, order *Order) error
@@ -4,6 +4,7 @@ const (
 	Placed    Status = "placed"
 	Shipped   Status = "shipped"
 	Delivered Status = "delivered"
+	Cancelled Status = "cancelled"
 )
 
 // Status represent an order status
@@ -18,3 +19,13 @@ func (s Status) IsZero() bool {
 func (s Status) String() string {
 	return string(s)
 }
+
+// isKnownStatus reports whether s is one of the statuses of the order
+func isKnownStatus(s Status) bool {
+	switch s {
+	case Placed, Shipped, Delivered, Cancelled:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,35 @@
+package order
+
+import (
+	"errors"
+	"regexp"
+)
+
+var (
+	// ErrTenantNotParsed represents an error returned by a tenant value type (aka value objects)
+	ErrTenantNotParsed = errors.New("could not parse tenant")
+)
+
+var tenantRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// Tenant represents the sales channel an order is placed through (e.g. web, store, marketplace)
+type Tenant string
+
+// ParseTenant returns a Tenant or an error if the given string is not a valid Tenant
+func ParseTenant(s string) (Tenant, error) {
+	if !tenantRegexp.MatchString(s) {
+		return "", ErrTenantNotParsed
+	}
+
+	return Tenant(s), nil
+}
+
+// IsZero reports whether t represents the zero Tenant
+func (t Tenant) IsZero() bool {
+	return t == ""
+}
+
+// String returns the Tenant as string
+func (t Tenant) String() string {
+	return string(t)
+}
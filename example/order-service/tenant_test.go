@@ -0,0 +1,31 @@
+package order_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/organization/order-service"
+)
+
+func TestParseTenant(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		for _, raw := range []string{"web", "store-42", "marketplace_eu"} {
+			tn, err := ParseTenant(raw)
+			if err != nil {
+				t.Fatalf("could not parse tenant %q: %s", raw, err)
+			}
+
+			if tn.String() != raw {
+				t.Errorf("could not match tenant: %s", tn)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, raw := range []string{"", "Web", "-web", "web shop"} {
+			if _, err := ParseTenant(raw); !errors.Is(err, ErrTenantNotParsed) {
+				t.Fatalf("could not match error for %q: %s", raw, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,189 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Guard represents an additional invariant checked before a Transition is applied
+// It returns an error describing the violated invariant
+type Guard func(o *Order) error
+
+// Transition represents an allowed change of Status of an order
+type Transition struct {
+	From  Status
+	To    Status
+	Guard Guard
+}
+
+// TransitionError represents an error returned when an order could not change its Status
+// It wraps the domain error of the command that requested the transition (e.g. ErrNotShipped)
+type TransitionError struct {
+	From   Status
+	To     Status
+	Err    error
+	Reason error
+}
+
+// Error returns the TransitionError as string
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Err, e.Reason)
+}
+
+// Unwrap returns the domain errors wrapped by the TransitionError
+func (e *TransitionError) Unwrap() []error {
+	return []error{e.Err, e.Reason}
+}
+
+// transitions represents the order status state machine
+// Every aggregate command changing the Status must go through it
+var transitions = []Transition{
+	{From: Placed, To: Shipped, Guard: mustBePlaced},
+	{From: Placed, To: Cancelled, Guard: mustBePlaced},
+	{From: Shipped, To: Delivered, Guard: mustBeShipped},
+	{From: Shipped, To: Cancelled, Guard: mustBeShipped},
+}
+
+// transitionErrs maps a target Status to the domain error returned when it cannot be reached
+var transitionErrs = map[Status]error{
+	Shipped:   ErrNotShipped,
+	Delivered: ErrNotDelivered,
+	Cancelled: ErrNotCancelled,
+}
+
+// Transitions returns all the transitions allowed by the order status state machine
+func Transitions() []Transition {
+	ts := make([]Transition, len(transitions))
+	copy(ts, transitions)
+	return ts
+}
+
+// CanTransitionTo reports whether the state machine allows moving from s to the given Status
+// It does not evaluate the guards, as they depend on the order
+func (s Status) CanTransitionTo(to Status) bool {
+	_, ok := findTransition(s, to)
+	return ok
+}
+
+// transitionTo moves the order to the given Status if the state machine allows it
+// It returns a TransitionError when the transition is not allowed or a guard failed
+func (o *Order) transitionTo(to Status) error {
+	t, ok := findTransition(o.Status, to)
+	if !ok {
+		return o.transitionErr(to, rejectionReason(o.Status, to))
+	}
+
+	if t.Guard != nil {
+		if err := t.Guard(o); err != nil {
+			return o.transitionErr(to, err)
+		}
+	}
+
+	o.Status = to
+	return nil
+}
+
+func (o *Order) transitionErr(to Status, reason error) error {
+	err, ok := transitionErrs[to]
+	if !ok {
+		err = fmt.Errorf("could not transition the order to %s", to)
+	}
+
+	return &TransitionError{From: o.Status, To: to, Err: err, Reason: reason}
+}
+
+func findTransition(from, to Status) (Transition, bool) {
+	for _, t := range transitions {
+		if t.From == from && t.To == to {
+			return t, true
+		}
+	}
+
+	return Transition{}, false
+}
+
+func rejectionReason(from, to Status) error {
+	switch {
+	case from.IsZero():
+		return errors.New("not placed")
+	case from == Placed && to == Delivered:
+		return errors.New("not shipped")
+	default:
+		return fmt.Errorf("already %s", from)
+	}
+}
+
+func mustBePlaced(o *Order) error {
+	if o.PlacedAt.IsZero() {
+		return errors.New("not placed")
+	}
+
+	return nil
+}
+
+func mustBeShipped(o *Order) error {
+	if o.ShippedAt.IsZero() {
+		return errors.New("not shipped")
+	}
+
+	return nil
+}
+
+// MermaidDiagram renders the order status state machine as a Mermaid state diagram
+func MermaidDiagram() string {
+	var b strings.Builder
+	b.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&b, "    [*] --> %s\n", Placed)
+	for _, t := range transitions {
+		fmt.Fprintf(&b, "    %s --> %s\n", t.From, t.To)
+	}
+	for _, s := range finalStatuses() {
+		fmt.Fprintf(&b, "    %s --> [*]\n", s)
+	}
+
+	return b.String()
+}
+
+// GraphvizDiagram renders the order status state machine as a Graphviz digraph
+func GraphvizDiagram() string {
+	var b strings.Builder
+	b.WriteString("digraph order_status {\n")
+	b.WriteString("    rankdir=LR;\n")
+	for _, s := range finalStatuses() {
+		fmt.Fprintf(&b, "    %q [shape=doublecircle];\n", s)
+	}
+	for _, t := range transitions {
+		fmt.Fprintf(&b, "    %q -> %q;\n", t.From, t.To)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// finalStatuses returns the statuses with no outgoing transition, in the order they appear in the state machine
+func finalStatuses() []Status {
+	var ss []Status
+	seen := map[Status]bool{}
+	for _, t := range transitions {
+		if seen[t.To] {
+			continue
+		}
+		seen[t.To] = true
+		if !hasOutgoingTransition(t.To) {
+			ss = append(ss, t.To)
+		}
+	}
+
+	return ss
+}
+
+func hasOutgoingTransition(s Status) bool {
+	for _, t := range transitions {
+		if t.From == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,123 @@
+package order_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/organization/order-service"
+)
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to Status
+		want     bool
+	}{
+		{from: Placed, to: Shipped, want: true},
+		{from: Placed, to: Delivered, want: false},
+		{from: Placed, to: Cancelled, want: true},
+		{from: Shipped, to: Shipped, want: false},
+		{from: Shipped, to: Delivered, want: true},
+		{from: Shipped, to: Cancelled, want: true},
+		{from: Delivered, to: Shipped, want: false},
+		{from: Delivered, to: Cancelled, want: false},
+		{from: Cancelled, to: Shipped, want: false},
+		{from: Cancelled, to: Delivered, want: false},
+		{from: Status(""), to: Shipped, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+			t.Errorf("could not match transition from %q to %q", tt.from, tt.to)
+			t.Errorf("got: %t", got)
+			t.Errorf("want: %t", tt.want)
+		}
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	for _, tr := range Transitions() {
+		if !tr.From.CanTransitionTo(tr.To) {
+			t.Errorf("could not match listed transition from %q to %q as allowed", tr.From, tr.To)
+		}
+	}
+}
+
+func TestTransitionError(t *testing.T) {
+	clock := clockHelper(t)
+	t.Run("not allowed", func(t *testing.T) {
+		o := &Order{Status: Placed, PlacedAt: clock.Now()}
+
+		err := o.MarkAsDelivered(clock)
+		if !errors.Is(err, ErrNotDelivered) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		var tErr *TransitionError
+		if !errors.As(err, &tErr) {
+			t.Fatalf("could not match transition error: %s", err)
+		}
+
+		if tErr.From != Placed || tErr.To != Delivered {
+			t.Errorf("could not match transition")
+			t.Errorf("got: %s -> %s", tErr.From, tErr.To)
+			t.Errorf("want: %s -> %s", Placed, Delivered)
+		}
+
+		if o.Status != Placed {
+			t.Errorf("could not match unchanged status: %s", o.Status)
+		}
+	})
+
+	t.Run("guard failed", func(t *testing.T) {
+		o := &Order{Status: Placed}
+
+		err := o.MarkAsShipped(clock)
+		if !errors.Is(err, ErrNotShipped) {
+			t.Fatalf("could not match error: %s", err)
+		}
+
+		var tErr *TransitionError
+		if !errors.As(err, &tErr) {
+			t.Fatalf("could not match transition error: %s", err)
+		}
+
+		if o.Status != Placed {
+			t.Errorf("could not match unchanged status: %s", o.Status)
+		}
+	})
+}
+
+func TestMermaidDiagram(t *testing.T) {
+	d := MermaidDiagram()
+
+	for _, line := range []string{
+		"stateDiagram-v2",
+		"[*] --> placed",
+		"placed --> shipped",
+		"shipped --> delivered",
+		"shipped --> cancelled",
+		"delivered --> [*]",
+		"cancelled --> [*]",
+	} {
+		if !strings.Contains(d, line) {
+			t.Errorf("could not find %q in diagram:\n%s", line, d)
+		}
+	}
+}
+
+func TestGraphvizDiagram(t *testing.T) {
+	d := GraphvizDiagram()
+
+	for _, line := range []string{
+		"digraph order_status {",
+		`"placed" -> "shipped";`,
+		`"placed" -> "cancelled";`,
+		`"shipped" -> "delivered";`,
+		`"delivered" [shape=doublecircle];`,
+	} {
+		if !strings.Contains(d, line) {
+			t.Errorf("could not find %q in diagram:\n%s", line, d)
+		}
+	}
+}